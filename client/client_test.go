@@ -12,6 +12,39 @@ import (
 	c "../common"
 )
 
+func TestGrantRole(t *testing.T) {
+	// the bank is admin on every account: it grants the first roles
+	opts := c.PayloadFields{
+		KeysFile:      "/home/majed/.sawtooth/keys/bank",
+		RequestType:   "grant_role",
+		SourceAccount: "AB12XF3",
+	}
+
+	t.Run("majed", func(t *testing.T) {
+		opts.PubKeys = "030509c81f5d5e927cd2fbe17ac1e90866e53deec7bba53670afec5e562ef53f9d" // majed.pub
+		opts.Role = "bank_admin"
+		execute(opts)
+	})
+
+	t.Run("CD34YG4", func(t *testing.T) {
+		opts.PubKeys = "03cf7cfa4a7ce9a5517ab424fc6bc5821db1dc3a2b55483683549947caa5a8a60e"
+		opts.Role = "signer"
+		execute(opts)
+	})
+}
+
+func TestRevokeRole(t *testing.T) {
+	opts := c.PayloadFields{
+		KeysFile:      "/home/majed/.sawtooth/keys/majed",
+		RequestType:   "revoke_role",
+		SourceAccount: "AB12XF3",
+		PubKeys:       "03cf7cfa4a7ce9a5517ab424fc6bc5821db1dc3a2b55483683549947caa5a8a60e",
+		Role:          "signer",
+	}
+
+	execute(opts)
+}
+
 func TestSetInitiatorPubKeys(t *testing.T) {
 	opts := c.PayloadFields{
 		KeysFile:      "/home/majed/.sawtooth/keys/majed",
@@ -225,6 +258,28 @@ func TestQueryAuth(t *testing.T) {
 	// })
 }
 
+func TestSimulateAuth(t *testing.T) {
+	opts := c.PayloadFields{
+		KeysFile:      "/home/majed/.sawtooth/keys/majed",
+		RequestType:   "simulate_auth",
+		SourceAccount: "AB12XF3",
+		Initiator:     "ID12345",
+		Amount:        11000,
+	}
+
+	// same as query auth, but no pending tx is set
+	t.Run("rules in force", func(t *testing.T) {
+		execute(opts)
+	})
+
+	// preview of an account level rule
+	t.Run("extra rule", func(t *testing.T) {
+		opts.Group = c.DefaultGroupName
+		opts.Rule = "Amount > 10000 ? 'deny' : 'nil'"
+		execute(opts)
+	})
+}
+
 func TestListPendingTx(t *testing.T) {
 	opts := c.PayloadFields{
 		KeysFile:      "/home/majed/.sawtooth/keys/majed",
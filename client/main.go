@@ -27,13 +27,14 @@ func main() {
 		fmt.Println("Warning: extraneous options")
 	}
 
-	// TODO handle case where keys file doesn't exist
-	privateKey, publicKey := c.GetKeysFromFiles(opts.KeysFile)
-	p := c.CreateSignedPayload(&opts, &privateKey, &publicKey)
-
-	pEnc, err := json.Marshal(*p)
-	if err != nil {
-		panic(err)
+	var pEnc []byte
+	if opts.Manifest != "" {
+		pEnc = createBatch(&opts)
+	} else {
+		pEnc, err = json.Marshal(*createSignedPayload(&opts))
+		if err != nil {
+			panic(err)
+		}
 	}
 	// // // // // // req, err := http.NewRequest("POST", url, bytes.NewBuffer(pEnc))
 	// // // // // // if err != nil {
@@ -68,3 +69,49 @@ func main() {
 	}
 
 }
+
+func createSignedPayload(opts *c.PayloadFields) *c.SignedPayload {
+	// core serves every request type common knows of, see common.RequestKind()
+	if _, ok := c.RequestKind(opts.RequestType); !ok {
+		fmt.Println("Error: unknown request type " + opts.RequestType)
+		os.Exit(1)
+	}
+
+	// TODO handle case where keys file doesn't exist
+	privateKey, publicKey := c.GetKeysFromFiles(opts.KeysFile)
+	return c.CreateSignedPayload(opts, &privateKey, &publicKey)
+}
+
+// createBatch the signed payloads of the requests in the manifest, a json list of objects with the fields of c.PayloadFields, e.g., [{"RequestType": "add_initiator_to_group", "SourceAccount": "1234", ...}, ...]. requests without a keys file are signed with the one on the command line
+func createBatch(opts *c.PayloadFields) []byte {
+	buf, err := ioutil.ReadFile(opts.Manifest)
+	if err != nil {
+		panic(err)
+	}
+	var requests []c.PayloadFields
+	err = json.Unmarshal(buf, &requests)
+	if err != nil {
+		fmt.Println("Error: malformed manifest " + opts.Manifest)
+		os.Exit(1)
+	}
+
+	ps := make([]*c.SignedPayload, len(requests))
+	for i := range requests {
+		if requests[i].KeysFile == "" {
+			requests[i].KeysFile = opts.KeysFile
+		}
+		// only requests that change the state, and are nothing but a transaction, make it into a batch
+		if kind, ok := c.RequestKind(requests[i].RequestType); ok && kind != c.RequestTx {
+			fmt.Println("Error: " + requests[i].RequestType + " requests cannot be batched")
+			os.Exit(1)
+		}
+		ps[i] = createSignedPayload(&requests[i])
+	}
+
+	ret, err := json.Marshal(ps)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
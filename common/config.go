@@ -3,31 +3,32 @@ package common
 // TODO Still have to figure out how to impose authentication on REST API
 const (
 	ValidatorEndpoint     string = "tcp://localhost:4004"
-	RestAPIBatches        string = "http://127.0.0.1:8008/batches"
-	RestAPIState          string = "http://127.0.0.1:8008/state"
-	RestAPIBatchStatuses  string = "http://127.0.0.1:8008/batch_statuses"
+	RestAPI               string = "http://127.0.0.1:8008" // the default state backend of core, see core.SetStateBackend()
+	RestAPIBatches        string = RestAPI + "/batches"
+	RestAPIState          string = RestAPI + "/state"
+	RestAPIBatchStatuses  string = RestAPI + "/batch_statuses"
 	RestAPIWait           string = "300"
+	RestAPIPageLimit      int    = 1000 // entries per page of state, the most the rest api serves
+	StatusWebhook         string = ""   // where the final status of submitted batches is posted, none if empty. TODO set the bank's
+	IndexedAccounts       string = ""   // comma-separated accounts whose state is read from a local index, see core.Indexer. none if empty
 	APIGateway            string = "http://127.0.0.1:3000/"
 	AuthUser              string = ""
 	AuthPassword          string = ""
 	BatchSignerKeysFile   string = "/home/majed/.sawtooth/keys/bank"
 	BatchSignerPubKeyFile string = "/home/majed/.sawtooth/keys/bank.pub"
+	BankPubKey            string = ""                                       // hex public key of the bank, admin on every account. set it so that every validator checks against the same key whatever its key files. read from BatchSignerPubKeyFile if empty
+	CoreBankingFile       string = "/home/majed/.sawtooth/corebanking.json" // reference core banking system. TODO plug in the bank's
+	RuleTimeZone          string = "UTC"                                    // time zone of the clock rule variables, holidays and dates in rules, see RuleLocation
+)
+
+// Versions of SignedPayload. The version is the transaction family version of the transactions the payload ends up in. Payloads without a version predate versioning
+const (
+	PayloadVersion       string = "0.3" // protobuf, see ../protos
+	JSONPayloadVersion   string = "0.2" // the last version with json payloads
+	LegacyPayloadVersion string = "0.1"
 )
 
 // Every ID authorized to use the account is part of this group. This is useful for setting account level rules for sign-offs, etc.,
 const (
 	DefaultGroupName string = "Everyone" // this is used to set account level rules. Everyone belongs to this group.
 )
-
-// RuleVariablesSet defines the variables we expect in an expression. TODO how exhaustive can we be? TODO how are our functions treated?
-var RuleVariablesSet = map[string]bool{ // Keep it in alphabetical order for easy reading
-	"Action":        true,
-	"Amount":        true,
-	"Balance":       true,
-	"Destaccount":   true,
-	"Initiator":     true,
-	"Recipient":     true,
-	"Rule":          true,
-	"Ruletype":      true, // this is not used currently, right?
-	"Sourceaccount": true,
-}
@@ -0,0 +1,206 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	// registers the messages looked up by name below
+	_ "../protos"
+)
+
+// protoPackage of the messages in ../protos
+const protoPackage = "bank."
+
+// MarshalProto encodes m deterministically, i.e., the same bytes on every validator whatever the order of its maps
+func MarshalProto(m proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+}
+
+// IsJSONPayload tells payloads of versions before 0.3, which are json, from protobuf ones. Note: clients marshal payloads with json.Marshal so a json payload starts with '{', which is never the first byte of one of our messages (it would be field 15, a group). Don't skip white space: '\n' is the tag of field 1
+func IsJSONPayload(pl []byte) bool {
+	return len(pl) > 0 && pl[0] == '{'
+}
+
+// EncodePayload encodes the payload struct v with the message of the same name in ../protos, e.g., PayloadQueryAuth
+func EncodePayload(v interface{}) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	mt, err := messageType(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	m := mt.New()
+	err = toMessage(rv, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalProto(m.Interface())
+}
+
+// DecodePayload decodes pl into v, a pointer to a payload struct, whatever the version of pl: json before 0.3, protobuf after
+func DecodePayload(pl []byte, v interface{}) error {
+	if IsJSONPayload(pl) {
+		return json.Unmarshal(pl, v)
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	mt, err := messageType(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	m := mt.New()
+	err = proto.Unmarshal(pl, m.Interface())
+	if err != nil {
+		return err
+	}
+
+	return fromMessage(m, rv)
+}
+
+// messageType the message for struct type t. types defined on payload structs, e.g., core.PayloadQueryAuth, have the same name
+func messageType(t reflect.Type) (protoreflect.MessageType, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(protoPackage + t.Name()))
+	if err != nil {
+		return nil, errors.New("no protobuf message for " + t.Name())
+	}
+
+	return mt, nil
+}
+
+// messageField the field of md for struct field name, e.g., TransactionID for transaction_id
+func messageField(md protoreflect.MessageDescriptor, name string) (protoreflect.FieldDescriptor, error) {
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if strings.EqualFold(strings.Replace(string(fields.Get(i).Name()), "_", "", -1), name) {
+			return fields.Get(i), nil
+		}
+	}
+
+	return nil, errors.New("no field " + name + " in " + string(md.FullName()))
+}
+
+func toMessage(v reflect.Value, m protoreflect.Message) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fd, err := messageField(m.Descriptor(), t.Field(i).Name)
+		if err != nil {
+			return err
+		}
+
+		f := v.Field(i)
+		if !fd.IsList() {
+			pv, err := toValue(fd, f)
+			if err != nil {
+				return err
+			}
+			m.Set(fd, pv)
+			continue
+		}
+
+		l := m.Mutable(fd).List()
+		for j := 0; j < f.Len(); j++ {
+			if fd.Kind() != protoreflect.MessageKind {
+				pv, err := toValue(fd, f.Index(j))
+				if err != nil {
+					return err
+				}
+				l.Append(pv)
+				continue
+			}
+			e := l.NewElement()
+			err = toMessage(f.Index(j), e.Message())
+			if err != nil {
+				return err
+			}
+			l.Append(e)
+		}
+	}
+
+	return nil
+}
+
+func fromMessage(m protoreflect.Message, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fd, err := messageField(m.Descriptor(), t.Field(i).Name)
+		if err != nil {
+			return err
+		}
+
+		f := v.Field(i)
+		if !fd.IsList() {
+			fromValue(m.Get(fd), f)
+			continue
+		}
+
+		l := m.Get(fd).List()
+		if l.Len() == 0 {
+			continue
+		}
+		s := reflect.MakeSlice(f.Type(), l.Len(), l.Len())
+		for j := 0; j < l.Len(); j++ {
+			if fd.Kind() != protoreflect.MessageKind {
+				fromValue(l.Get(j), s.Index(j))
+				continue
+			}
+			err = fromMessage(l.Get(j).Message(), s.Index(j))
+			if err != nil {
+				return err
+			}
+		}
+		f.Set(s)
+	}
+
+	return nil
+}
+
+// only the kinds found in payloads are handled. ints that don't fit the field are an error, not truncated
+func toValue(fd protoreflect.FieldDescriptor, f reflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(f.String()), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(f.Bytes()), nil
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(f.Float()), nil
+	case protoreflect.Int64Kind:
+		return protoreflect.ValueOfInt64(f.Int()), nil
+	case protoreflect.Int32Kind:
+		n := f.Int()
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return protoreflect.Value{}, errors.New(string(fd.Name()) + " out of range")
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(f.Bool()), nil
+	}
+
+	panic("no conversion to " + fd.Kind().String())
+}
+
+func fromValue(pv protoreflect.Value, f reflect.Value) {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(pv.String())
+	case reflect.Slice:
+		// []byte, the only slice that isn't a list
+		f.SetBytes(pv.Bytes())
+	case reflect.Float32, reflect.Float64:
+		f.SetFloat(pv.Float())
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		f.SetInt(pv.Int())
+	case reflect.Bool:
+		f.SetBool(pv.Bool())
+	default:
+		panic("no conversion to " + f.Kind().String())
+	}
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestIsJSONPayload(t *testing.T) {
+	js, err := json.Marshal(PayloadDeleteInitiatorRule{SourceAccount: "AB12XF3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsJSONPayload(js) {
+		t.Errorf("IsJSONPayload(%s) = false", js)
+	}
+
+	// field 1 of 123 bytes, i.e., '\n' then '{'
+	pl, err := EncodePayload(&PayloadDeleteInitiatorRule{SourceAccount: strings.Repeat("A", '{')})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsJSONPayload(pl) {
+		t.Errorf("IsJSONPayload(%q) = true", pl[:2])
+	}
+	var v PayloadDeleteInitiatorRule
+	if err := DecodePayload(pl, &v); err != nil || len(v.SourceAccount) != '{' {
+		t.Errorf("DecodePayload() = %v, %d bytes of account", err, len(v.SourceAccount))
+	}
+}
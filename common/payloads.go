@@ -2,8 +2,9 @@ package common
 
 import (
 	"encoding/hex"
-	"encoding/json"
+	"sort"
 	"strings"
+	"time"
 
 	sgn "github.com/hyperledger/sawtooth-sdk-go/signing"
 )
@@ -26,6 +27,16 @@ type PayloadFields struct {
 	Signature     string  `long:"signature" description:"signature for a pending transaction"`
 	TransactionID string  `long:"transactionid" description:"system generated id displayed to user"`
 	InitiatorKey  string  `long:"initiatorkey" description:"the initiator public key"`
+	Role          string  `long:"role" description:"role granted or revoked: bank_admin, rule_setter, transactor or signer"`
+	PermissionTag string  `long:"permissiontag" description:"permission tag the role is granted on. empty as of this version"`
+	BatchID       string  `long:"batchid" description:"id of the batch a state changing request was submitted in"`
+	Manifest      string  `long:"manifest" description:"json file with a list of requests, each with the fields of these options, to submit as one atomic batch"`
+	Policy        string  `long:"policy" description:"default policy of the account: allow_unless_denied or deny_unless_allowed"`
+	Holidays      string  `long:"holidays" description:"(comma-separated) holidays of the account, e.g., 2026-12-25. replaces its holiday calendar"`
+	ValidFrom     string  `long:"validfrom" description:"date or time the rule being set comes into force, e.g., 2026-12-01 or 2026-12-01T09:00:00Z. now if empty"`
+	ValidUntil    string  `long:"validuntil" description:"date or time the rule being set expires, e.g., 2026-12-30. never if empty"`
+	Priority      int     `long:"priority" description:"priority of the rule being set. rules of higher priority that fire decide over those of lower priority"`
+	Override      string  `long:"override" description:"whether the rule being set can be overridden: overridable, the default, or final. only bank admins set final rules"`
 }
 
 // Important Note: this should have every type of payload
@@ -40,6 +51,7 @@ var payloadToCreatorMethod = map[string]func(*map[string]interface{}) []byte{
 	"delete_initiator_pub_keys":   deleteInitiatorPubKeys,
 	"list_initiator_pub_keys":     listInitiatorPubKeys,
 	"query_auth":                  queryAuth,
+	"simulate_auth":               simulateAuth,
 	"close_pending_tx":            closePendingTx,
 	"add_sig_tx":                  addSigTx,
 	"list_pending_tx":             listPendingTx,
@@ -49,6 +61,68 @@ var payloadToCreatorMethod = map[string]func(*map[string]interface{}) []byte{
 	"set_account_level_rule":      setAccountLevelRule,
 	"delete_account_level_rule":   deleteAccountLevelRule,
 	"list_account_level_rules":    listAccountLevelRules,
+	"grant_role":                  grantRole,
+	"revoke_role":                 revokeRole,
+	"batch_status":                batchStatus,
+	"set_account_policy":          setAccountPolicy,
+	"get_account_policy":          getAccountPolicy,
+	"set_holidays":                setHolidays,
+	"list_holidays":               listHolidays,
+}
+
+// Kinds of request, by how the lambda serves them
+const (
+	RequestQuery     string = "query"      // answered from the state. Note: query_auth may submit a set_pending_tx transaction on top
+	RequestTx        string = "tx"         // changes the state with a transaction of its own. only these can be batched
+	RequestHandledTx string = "handled_tx" // changes the state but the lambda does more than submitting its transaction, e.g., the bank signs add_sig_tx
+)
+
+// Important Note: like payloadToCreatorMethod, this should have every type of payload. core checks at startup that it serves each one as its kind says
+var requestKinds = map[string]string{
+	"set_initiator_rule":          RequestTx,
+	"delete_initiator_rule":       RequestTx,
+	"list_initiator_rules":        RequestQuery,
+	"add_initiator_to_group":      RequestTx,
+	"remove_initiator_from_group": RequestTx,
+	"list_initiator_groups":       RequestQuery,
+	"set_initiator_pub_keys":      RequestTx,
+	"delete_initiator_pub_keys":   RequestTx,
+	"list_initiator_pub_keys":     RequestQuery,
+	"query_auth":                  RequestQuery,
+	"simulate_auth":               RequestQuery,
+	"close_pending_tx":            RequestTx,
+	"add_sig_tx":                  RequestHandledTx,
+	"list_pending_tx":             RequestQuery,
+	"set_recipient":               RequestTx,
+	"remove_recipient":            RequestTx,
+	"list_recipient":              RequestQuery,
+	"set_account_level_rule":      RequestTx,
+	"delete_account_level_rule":   RequestTx,
+	"list_account_level_rules":    RequestQuery,
+	"grant_role":                  RequestTx,
+	"revoke_role":                 RequestTx,
+	"batch_status":                RequestQuery,
+	"set_account_policy":          RequestTx,
+	"get_account_policy":          RequestQuery,
+	"set_holidays":                RequestTx,
+	"list_holidays":               RequestQuery,
+}
+
+// RequestKind returns the kind of request type t, false if CreateSignedPayload() can't create it
+func RequestKind(t string) (string, bool) {
+	k, ok := requestKinds[t]
+	return k, ok
+}
+
+// PayloadTypes returns the types of request CreateSignedPayload() can create. core checks at startup that it serves them all
+func PayloadTypes() []string {
+	ret := make([]string, 0, len(payloadToCreatorMethod))
+	for t := range payloadToCreatorMethod {
+		ret = append(ret, t)
+	}
+	sort.Strings(ret)
+
+	return ret
 }
 
 // CreateSignedPayload (typically) from command line arguments
@@ -70,7 +144,7 @@ func CreateSignedPayload(opts *PayloadFields, signerPrivateKey *sgn.PrivateKey,
 	signer := GetSigner(*signerPrivateKey)
 	signature := signer.Sign(p)
 
-	return &SignedPayload{SourceAccount: opts.SourceAccount, Type: payloadType, SignerPubKey: (*signerPubKey).AsBytes(), Signature: signature, Payload: p}
+	return &SignedPayload{Version: PayloadVersion, SourceAccount: opts.SourceAccount, Type: payloadType, SignerPubKey: (*signerPubKey).AsBytes(), Signature: signature, Payload: p}
 }
 
 func setInitiatorRule(mp *map[string]interface{}) []byte {
@@ -84,9 +158,13 @@ func setInitiatorRule(mp *map[string]interface{}) []byte {
 		SourceAccount: a,
 		Initiator:     i,
 		Rule:          r,
+		ValidFrom:     parseValidity(m["ValidFrom"].(string)),
+		ValidUntil:    parseValidity(m["ValidUntil"].(string)),
+		Priority:      m["Priority"].(int),
+		Override:      m["Override"].(string),
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -94,6 +172,23 @@ func setInitiatorRule(mp *map[string]interface{}) []byte {
 	return pEnc
 }
 
+// parseValidity the unix time of a date, in RuleLocation like dates in rules, or of a RFC 3339 time. 0 for ""
+func parseValidity(s string) int64 {
+	if s == "" {
+		return 0
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", s, RuleLocation)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic("malformed date or time " + s + ". expecting yyyy-mm-dd or RFC 3339")
+		}
+	}
+
+	return t.Unix()
+}
+
 func deleteInitiatorRule(mp *map[string]interface{}) []byte {
 	m := *mp
 
@@ -107,7 +202,7 @@ func deleteInitiatorRule(mp *map[string]interface{}) []byte {
 		RuleHash:      r,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -126,7 +221,7 @@ func listInitiatorRules(mp *map[string]interface{}) []byte {
 		Initiator:     i,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -146,7 +241,7 @@ func addInitiatorToGroup(mp *map[string]interface{}) []byte {
 		Group:         g,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -167,7 +262,7 @@ func removeInitiatorFromGroup(mp *map[string]interface{}) []byte {
 		Group:         g,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -186,7 +281,7 @@ func listInitiatorGroups(mp *map[string]interface{}) []byte {
 		Initiator:     i,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -207,7 +302,7 @@ func setInitiatorPubKeys(mp *map[string]interface{}) []byte {
 		PubKeys:       strings.Split(g, ","),
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -228,7 +323,7 @@ func deleteInitiatorPubKeys(mp *map[string]interface{}) []byte {
 		PubKeys:       strings.Split(g, ","),
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -247,7 +342,7 @@ func listInitiatorPubKeys(mp *map[string]interface{}) []byte {
 		Initiator:     i,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -274,7 +369,44 @@ func queryAuth(mp *map[string]interface{}) []byte {
 		DestAccount:   d,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+// the rule from the command line, if any, is simulated on group when set, on initiator otherwise
+func simulateAuth(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+	i := m["Initiator"].(string)
+	r := m["Recipient"].(string)
+	c := m["Action"].(string)
+	n := m["Amount"].(float64)
+	d := m["DestAccount"].(string)
+	x := m["Rule"].(string)
+	g := m["Group"].(string)
+
+	payload := PayloadSimulateAuth{
+		SourceAccount: a,
+		Initiator:     i,
+		Recipient:     r,
+		Action:        c,
+		Amount:        n,
+		DestAccount:   d,
+	}
+	if x != "" {
+		on := i
+		if g != "" {
+			on = g
+		}
+		payload.ExtraRules = []HypotheticalRule{{Initiator: on, Rule: x}}
+	}
+
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -297,7 +429,7 @@ func closePendingTx(mp *map[string]interface{}) []byte {
 		TransactionID: t,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -336,7 +468,7 @@ func addSigTx(mp *map[string]interface{}) []byte {
 		PubKey:        pubKey,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -355,7 +487,7 @@ func listPendingTx(mp *map[string]interface{}) []byte {
 		Initiator:     i,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -376,7 +508,7 @@ func setRecipient(mp *map[string]interface{}) []byte {
 		DestAccount:   d,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -397,7 +529,7 @@ func removeRecipient(mp *map[string]interface{}) []byte {
 		DestAccount:   d,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -416,7 +548,7 @@ func listRecipient(mp *map[string]interface{}) []byte {
 		Recipient:     r,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -434,9 +566,13 @@ func setAccountLevelRule(mp *map[string]interface{}) []byte {
 		SourceAccount: a,
 		Initiator:     DefaultGroupName,
 		Rule:          r,
+		ValidFrom:     parseValidity(m["ValidFrom"].(string)),
+		ValidUntil:    parseValidity(m["ValidUntil"].(string)),
+		Priority:      m["Priority"].(int),
+		Override:      m["Override"].(string),
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -456,7 +592,7 @@ func deleteAccountLevelRule(mp *map[string]interface{}) []byte {
 		RuleHash:      r,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -474,7 +610,158 @@ func listAccountLevelRules(mp *map[string]interface{}) []byte {
 		Initiator:     DefaultGroupName,
 	}
 
-	pEnc, err := json.Marshal(payload)
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+func grantRole(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+	t := m["PermissionTag"].(string)
+	k := m["PubKeys"].(string)
+	r := m["Role"].(string)
+
+	if len(strings.Split(k, ",")) != 1 {
+		panic("roles are granted one key at a time")
+	}
+
+	payload := PayloadGrantRole{
+		SourceAccount: a,
+		PermissionTag: t,
+		PubKey:        k,
+		Role:          r,
+	}
+
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+func revokeRole(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+	t := m["PermissionTag"].(string)
+	k := m["PubKeys"].(string)
+	r := m["Role"].(string)
+
+	if len(strings.Split(k, ",")) != 1 {
+		panic("roles are revoked one key at a time")
+	}
+
+	payload := PayloadRevokeRole{
+		SourceAccount: a,
+		PermissionTag: t,
+		PubKey:        k,
+		Role:          r,
+	}
+
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+func batchStatus(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+	b := m["BatchID"].(string)
+
+	payload := PayloadBatchStatus{
+		SourceAccount: a,
+		BatchID:       b,
+	}
+
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+func setAccountPolicy(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+	p := m["Policy"].(string)
+
+	payload := PayloadSetAccountPolicy{
+		SourceAccount: a,
+		Policy:        p,
+	}
+
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+func getAccountPolicy(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+
+	payload := PayloadGetAccountPolicy{
+		SourceAccount: a,
+	}
+
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+func setHolidays(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+	h := m["Holidays"].(string)
+
+	// an empty list clears the calendar
+	var holidays []string
+	if h != "" {
+		holidays = strings.Split(h, ",")
+	}
+
+	payload := PayloadSetHolidays{
+		SourceAccount: a,
+		Holidays:      holidays,
+	}
+
+	pEnc, err := EncodePayload(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return pEnc
+}
+
+func listHolidays(mp *map[string]interface{}) []byte {
+	m := *mp
+
+	a := m["SourceAccount"].(string)
+
+	payload := PayloadListHolidays{
+		SourceAccount: a,
+	}
+
+	pEnc, err := EncodePayload(payload)
 	if err != nil {
 		panic(err)
 	}
@@ -484,6 +771,7 @@ func listAccountLevelRules(mp *map[string]interface{}) []byte {
 
 // SignedPayload satisfies an introspection need when unmarshalling payloads
 type SignedPayload struct {
+	Version       string `json:"version,omitempty"` // empty for LegacyPayloadVersion
 	SourceAccount string
 	Type          string
 	SignerPubKey  []byte
@@ -496,6 +784,10 @@ type PayloadSetInitiatorRule struct {
 	SourceAccount string
 	Initiator     string `json:"initiator"` // because every rule is attached to an initiator which can be a group. we don't need to know whether Initiator is a Transactor{} or a group. Why? the logic for setting the rule is the same.
 	Rule          string `json:"rule"`
+	ValidFrom     int64  `json:"valid_from,omitempty"`  // unix time the rule comes into force. 0 for at once
+	ValidUntil    int64  `json:"valid_until,omitempty"` // unix time the rule expires, excluded. 0 for never
+	Priority      int    `json:"priority,omitempty"`
+	Override      string `json:"override,omitempty"` // final or overridable. empty is overridable
 }
 
 // PayloadDeleteInitiatorRule for deleting existing rules. Users are assumed to choose to delete a rule after listing rules, in which hashes also appear
@@ -561,6 +853,23 @@ type PayloadQueryAuth struct {
 	DestAccount   string
 }
 
+// PayloadSimulateAuth for querying about acceptance/rejection of transactions without side effects: no pending tx is set when signatures are required. ExtraRules are evaluated as if they had been set, which is how rule changes are previewed
+type PayloadSimulateAuth struct {
+	SourceAccount string
+	Initiator     string
+	Recipient     string
+	Action        string
+	Amount        float64 `json:"amount"`
+	DestAccount   string
+	ExtraRules    []HypotheticalRule `json:"extra_rules"`
+}
+
+// HypotheticalRule is a rule that is not in the state, attached to Initiator which can be a group or DefaultGroupName for account level rules
+type HypotheticalRule struct {
+	Initiator string `json:"initiator"`
+	Rule      string `json:"rule"`
+}
+
 // PayloadClosePendingTx for closing pending tx so the user can cancel pending tx
 type PayloadClosePendingTx struct {
 	SourceAccount string
@@ -576,6 +885,7 @@ type PayloadAddSigTx struct {
 	Signature     []byte
 	PubKey        []byte // Initiator's public key needed to verify Signature
 	Initiator     string // this is the initiator who wants to add its signature
+	Time          int64  `json:",omitempty"` // set by the bank, not the client: unix time recorded in the spend ledger if this is the last signature required
 }
 
 // PayloadListPendingTx list all pending transactions that need this initiator's sig
@@ -604,6 +914,50 @@ type PayloadListRecipient struct {
 	Recipient     string
 }
 
+// PayloadGrantRole for giving the holder of PubKey a role on the account. Only bank admins can grant roles
+type PayloadGrantRole struct {
+	SourceAccount string
+	PermissionTag string // the role holds in the transaction families with this permission tag
+	PubKey        string `json:"pub_key"` // hex, like the keys in PayloadSetInitiatorPubKeys
+	Role          string `json:"role"`
+}
+
+// PayloadRevokeRole for taking a role away from the holder of PubKey
+type PayloadRevokeRole struct {
+	SourceAccount string
+	PermissionTag string
+	PubKey        string `json:"pub_key"`
+	Role          string `json:"role"`
+}
+
+// PayloadBatchStatus for the status of a batch submitted by a state changing request. the status is also posted to the webhook, if any, when the batch is committed or rejected
+type PayloadBatchStatus struct {
+	SourceAccount string
+	BatchID       string `json:"batch_id"`
+}
+
+// PayloadSetAccountPolicy for setting what query_auth decides when no rule does: allow_unless_denied, the default, or deny_unless_allowed, under which a banking transaction needs a rule returning 'allow' or NofM()
+type PayloadSetAccountPolicy struct {
+	SourceAccount string
+	Policy        string `json:"policy"`
+}
+
+// PayloadGetAccountPolicy for the default policy of the account
+type PayloadGetAccountPolicy struct {
+	SourceAccount string
+}
+
+// PayloadSetHolidays for replacing the holiday calendar of the account, which IsHoliday() and IsBusinessDay() read. dates are yyyy-mm-dd
+type PayloadSetHolidays struct {
+	SourceAccount string
+	Holidays      []string `json:"holidays"`
+}
+
+// PayloadListHolidays for the holiday calendar of the account
+type PayloadListHolidays struct {
+	SourceAccount string
+}
+
 // ResponseGateway meant to be sent back through AWS API gateway
 type ResponseGateway struct {
 	Response []string
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Knetic/govaluate"
+)
+
+// SMT sorts of the arguments and results of rule functions. Outcome is the sort of what rules return: functions of this sort, like NofM, make rule outcomes instead of computing values
+const (
+	SortBool    string = "Bool"
+	SortReal    string = "Real"
+	SortString  string = "String"
+	SortOutcome string = "Outcome"
+)
+
+// RuleFunction is a function that can be called in rules. Args and Returns are SMT sorts: they give the SMT signature of the function and arguments are checked against them before Func is called, so Func can take them for granted. govaluate passes numbers as float64
+type RuleFunction struct {
+	Name    string
+	Args    []string
+	Returns string
+	Func    govaluate.ExpressionFunction
+}
+
+// the registry of rule functions. see RegisterRuleFunction()
+var ruleFunctions = make(map[string]RuleFunction)
+
+// RegisterRuleFunction makes f available in rules. Functions register themselves in init() functions. Registering the same name twice panics
+func RegisterRuleFunction(f RuleFunction) {
+	if _, ok := ruleFunctions[f.Name]; ok {
+		panic("rule function " + f.Name + " registered twice")
+	}
+	ruleFunctions[f.Name] = f
+}
+
+// LookupRuleFunction returns the rule function registered under name
+func LookupRuleFunction(name string) (RuleFunction, bool) {
+	f, ok := ruleFunctions[name]
+	return f, ok
+}
+
+// RuleFunctionNames returns the names of all the registered rule functions, sorted
+func RuleFunctionNames() []string {
+	ret := make([]string, 0, len(ruleFunctions))
+	for name := range ruleFunctions {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+// Checked returns Func wrapped in a check of the number and the types of the arguments
+func (f RuleFunction) Checked() govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != len(f.Args) {
+			return nil, fmt.Errorf("%s() takes %d arguments, got %d", f.Name, len(f.Args), len(args))
+		}
+		for i, arg := range args {
+			ok := false
+			switch f.Args[i] {
+			case SortReal:
+				_, ok = arg.(float64)
+			case SortString:
+				_, ok = arg.(string)
+			case SortBool:
+				_, ok = arg.(bool)
+			}
+			if !ok {
+				return nil, fmt.Errorf("argument %d of %s() must be of type %s", i+1, f.Name, f.Args[i])
+			}
+		}
+		if f.Func == nil {
+			return nil, errors.New(f.Name + "() has no implementation")
+		}
+
+		return f.Func(args...)
+	}
+}
+
+// SMTSignature returns the signature of the function in a SMT-LIB 2 declare-fun, e.g., "(Real String) Real"
+func (f RuleFunction) SMTSignature() string {
+	s := "("
+	for i, a := range f.Args {
+		if i != 0 {
+			s += " "
+		}
+		s += a
+	}
+
+	return s + ") " + f.Returns
+}
@@ -0,0 +1,209 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/Knetic/govaluate"
+)
+
+// where the values of rule variables come from
+const (
+	BindPayload     string = "payload"      // the field of the banking transaction, see PayloadQueryAuth
+	BindCoreBanking string = "core_banking" // the core banking system of the bank, see core.SetCoreBanking()
+	BindClock       string = "clock"        // the time the banking transaction was queried at, recorded with it when it's pending. never the time the rule happens to be evaluated at
+)
+
+// RuleLocation is the time zone of the clock variables, of holidays and of the dates in rules, RuleTimeZone whatever the TZ of the machine: the lambda, the transaction processor and the replay tool all see the same days
+var RuleLocation = loadRuleLocation()
+
+func loadRuleLocation() *time.Location {
+	loc, err := time.LoadLocation(RuleTimeZone)
+	if err != nil {
+		panic(err)
+	}
+
+	return loc
+}
+
+// the formats govaluate takes date literals in, in its order
+var ruleDateFormats = []string{
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	time.Kitchen,
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02T15Z0700",
+	"2006-01-02T15:04Z0700",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05.999999999Z0700",
+}
+
+// ParseRule parses rule with govaluate, the way every rule is parsed, but with its dates in RuleLocation rather than in time.Local, where govaluate parses those without time zone. govaluate makes a TIME token of every string literal that is a date, in order, so the dates are parsed again from the literals
+func ParseRule(rule string, functions map[string]govaluate.ExpressionFunction) (*govaluate.EvaluableExpression, error) {
+	e, err := govaluate.NewEvaluableExpressionWithFunctions(rule, functions)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := ruleDates(rule)
+	tokens := append([]govaluate.ExpressionToken{}, e.Tokens()...)
+	k := 0
+	for i := range tokens {
+		if tokens[i].Kind != govaluate.TIME {
+			continue
+		}
+		if k == len(dates) {
+			return nil, errors.New("cannot find the date literals of rule " + rule)
+		}
+		tokens[i].Value = dates[k]
+		k++
+	}
+	if k == 0 {
+		return e, nil
+	}
+
+	return govaluate.NewEvaluableExpressionFromTokens(tokens)
+}
+
+// ruleDates the string literals of rule that are dates, parsed in RuleLocation. literals are read as govaluate does: between quotes, ' or ", with backslashes escaping anything, but not in [variables]
+func ruleDates(rule string) []time.Time {
+	var ret []time.Time
+	runes := []rune(rule)
+	for i := 0; i < len(runes); i++ {
+		variable := runes[i] == '['
+		if !variable && runes[i] != '\'' && runes[i] != '"' {
+			continue
+		}
+		end := func(r rune) bool { return r == '\'' || r == '"' }
+		if variable {
+			end = func(r rune) bool { return r == ']' }
+		}
+
+		var literal []rune
+		for i++; i < len(runes) && !end(runes[i]); i++ {
+			if runes[i] == '\\' && i+1 < len(runes) {
+				i++
+			}
+			literal = append(literal, runes[i])
+		}
+		if variable {
+			continue
+		}
+		for _, format := range ruleDateFormats {
+			t, err := time.ParseInLocation(format, string(literal), RuleLocation)
+			if err == nil {
+				ret = append(ret, t)
+				break
+			}
+		}
+	}
+
+	return ret
+}
+
+// RuleVariable is a variable rules can use. It's bound to the field Field of the banking transaction, or comes from elsewhere, see Bind. Type is the kind of its value as govaluate sees it, i.e., numbers are float64, Sort its SMT sort
+type RuleVariable struct {
+	Name  string
+	Bind  string
+	Field string
+	Type  reflect.Kind
+	Sort  string
+}
+
+// the registry of rule variables, the one place NewRule(), rule evaluation and verification get them from. see RegisterRuleVariable()
+var ruleVariables = make(map[string]RuleVariable)
+
+func init() {
+	RegisterRuleVariable(RuleVariable{Name: "Action", Bind: BindPayload, Field: "Action", Type: reflect.String, Sort: SortString})
+	RegisterRuleVariable(RuleVariable{Name: "Amount", Bind: BindPayload, Field: "Amount", Type: reflect.Float64, Sort: SortReal})
+	RegisterRuleVariable(RuleVariable{Name: "Balance", Bind: BindCoreBanking, Type: reflect.Float64, Sort: SortReal})
+	RegisterRuleVariable(RuleVariable{Name: "DestAccount", Bind: BindPayload, Field: "DestAccount", Type: reflect.String, Sort: SortString})
+	RegisterRuleVariable(RuleVariable{Name: "Initiator", Bind: BindPayload, Field: "Initiator", Type: reflect.String, Sort: SortString})
+	RegisterRuleVariable(RuleVariable{Name: "Recipient", Bind: BindPayload, Field: "Recipient", Type: reflect.String, Sort: SortString})
+	RegisterRuleVariable(RuleVariable{Name: "SourceAccount", Bind: BindPayload, Field: "SourceAccount", Type: reflect.String, Sort: SortString})
+	// unix time like the dates in rules, e.g., Now > '2026-01-01'. Date is midnight of the day, so that Date == '2026-12-25' holds all day. Note: govaluate wants a comparison with a date in parentheses before ?
+	RegisterRuleVariable(RuleVariable{Name: "Now", Bind: BindClock, Type: reflect.Float64, Sort: SortReal})
+	RegisterRuleVariable(RuleVariable{Name: "Date", Bind: BindClock, Type: reflect.Float64, Sort: SortReal})
+	RegisterRuleVariable(RuleVariable{Name: "Hour", Bind: BindClock, Type: reflect.Float64, Sort: SortReal})
+	RegisterRuleVariable(RuleVariable{Name: "Weekday", Bind: BindClock, Type: reflect.String, Sort: SortString})
+	// the names the first versions whitelisted but never bound. rules in the state may use them
+	RegisterRuleVariable(RuleVariable{Name: "Destaccount", Bind: BindPayload, Field: "DestAccount", Type: reflect.String, Sort: SortString})
+	RegisterRuleVariable(RuleVariable{Name: "Sourceaccount", Bind: BindPayload, Field: "SourceAccount", Type: reflect.String, Sort: SortString})
+}
+
+// RegisterRuleVariable makes v available in rules. Registering the same name twice, or a variable that isn't bound, panics
+func RegisterRuleVariable(v RuleVariable) {
+	if _, ok := ruleVariables[v.Name]; ok {
+		panic("rule variable " + v.Name + " registered twice")
+	}
+	err := checkRuleVariable(v, reflect.TypeOf(PayloadQueryAuth{}))
+	if err != nil {
+		panic(err)
+	}
+
+	ruleVariables[v.Name] = v
+}
+
+// LookupRuleVariable returns the rule variable registered under name
+func LookupRuleVariable(name string) (RuleVariable, bool) {
+	v, ok := ruleVariables[name]
+	return v, ok
+}
+
+// RuleVariableNames returns the names of all the registered rule variables, sorted
+func RuleVariableNames() []string {
+	ret := make([]string, 0, len(ruleVariables))
+	for name := range ruleVariables {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+// BindRuleVariables returns the values of the variables bound to the fields of the banking transaction q, a pointer to a PayloadQueryAuth or to a type defined on it, keyed by variable name. the others, e.g., Balance, are bound by core
+func BindRuleVariables(q interface{}) map[string]interface{} {
+	s := reflect.Indirect(reflect.ValueOf(q))
+	m := make(map[string]interface{}, len(ruleVariables))
+	for name, v := range ruleVariables {
+		if v.Bind != BindPayload {
+			continue
+		}
+		// RegisterRuleVariable() made sure the field is there, with the right type
+		m[name] = s.FieldByName(v.Field).Interface()
+	}
+
+	return m
+}
+
+// checkRuleVariable v must be bound: to a field of t, the type of banking transactions, of its type, or to another known source
+func checkRuleVariable(v RuleVariable, t reflect.Type) error {
+	switch v.Bind {
+	case BindPayload:
+		f, ok := t.FieldByName(v.Field)
+		if !ok {
+			return fmt.Errorf("rule variable %s is bound to %s, which is not a field of %s", v.Name, v.Field, t.Name())
+		}
+		if f.Type.Kind() != v.Type {
+			return fmt.Errorf("rule variable %s is of type %s but %s.%s is of type %s", v.Name, v.Type, t.Name(), v.Field, f.Type.Kind())
+		}
+	case BindCoreBanking, BindClock:
+	default:
+		return fmt.Errorf("rule variable %s is not bound", v.Name)
+	}
+
+	if (v.Type == reflect.String) != (v.Sort == SortString) || (v.Type == reflect.Float64) != (v.Sort == SortReal) {
+		return fmt.Errorf("rule variable %s of type %s cannot be of sort %s", v.Name, v.Type, v.Sort)
+	}
+
+	return nil
+}
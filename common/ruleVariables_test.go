@@ -0,0 +1,42 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer func(l *time.Location) { RuleLocation = l }(RuleLocation)
+	RuleLocation = loc
+
+	tests := []struct {
+		rule string
+		now  string
+		want interface{}
+	}{
+		// midnight in New York is 5am UTC
+		{"(Now >= '2026-03-01') ? 'deny' : 'nil'", "2026-03-01T04:59:59Z", "nil"},
+		{"(Now >= '2026-03-01') ? 'deny' : 'nil'", "2026-03-01T05:00:00Z", "deny"},
+		{"(Now >= \"2026-03-01 09:30\") ? 'deny' : 'nil'", "2026-03-01T14:30:00Z", "deny"},
+		// dates with a time zone keep theirs
+		{"(Now >= '2026-03-01T00:00:00Z') ? 'deny' : 'nil'", "2026-03-01T00:00:00Z", "deny"},
+		// strings that aren't dates, escaped quotes included, don't shift the dates that follow
+		{"(Recipient != 'it\\'s' && Now >= '2026-03-01') ? 'deny' : 'nil'", "2026-03-01T04:00:00Z", "nil"},
+	}
+
+	for _, tt := range tests {
+		e, err := ParseRule(tt.rule, nil)
+		if err != nil {
+			t.Fatalf("ParseRule(%q): %v", tt.rule, err)
+		}
+		now, _ := time.Parse(time.RFC3339, tt.now)
+		got, err := e.Evaluate(map[string]interface{}{"Now": float64(now.Unix()), "Recipient": "bob"})
+		if err != nil || got != tt.want {
+			t.Errorf("%s at %s = %v, %v, want %v", tt.rule, tt.now, got, err, tt.want)
+		}
+	}
+}
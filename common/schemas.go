@@ -0,0 +1,196 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Schema is the subset of json schema needed to describe payloads. Nullable is for slices, which go marshals to null when empty
+type Schema struct {
+	Type            string             `json:"type"`
+	Properties      map[string]*Schema `json:"properties,omitempty"`
+	Required        []string           `json:"required,omitempty"`
+	Items           *Schema            `json:"items,omitempty"`
+	ContentEncoding string             `json:"contentEncoding,omitempty"`
+	Nullable        bool               `json:"nullable,omitempty"`
+}
+
+// Important Note: like payloadToCreatorMethod, this should have every type of payload. the schemas are derived from these structs so they can't drift apart
+var payloadToStruct = map[string]interface{}{
+	"set_initiator_rule":          PayloadSetInitiatorRule{},
+	"delete_initiator_rule":       PayloadDeleteInitiatorRule{},
+	"list_initiator_rules":        PayloadListInitiatorRules{},
+	"add_initiator_to_group":      PayloadAddInitiatorToGroup{},
+	"remove_initiator_from_group": PayloadRemoveInitiatorFromGroup{},
+	"list_initiator_groups":       PayloadListInitiatorGroups{},
+	"set_initiator_pub_keys":      PayloadSetInitiatorPubKeys{},
+	"delete_initiator_pub_keys":   PayloadDeleteInitiatorPubKeys{},
+	"list_initiator_pub_keys":     PayloadListInitiatorPubKeys{},
+	"query_auth":                  PayloadQueryAuth{},
+	"simulate_auth":               PayloadSimulateAuth{},
+	"close_pending_tx":            PayloadClosePendingTx{},
+	"add_sig_tx":                  PayloadAddSigTx{},
+	"list_pending_tx":             PayloadListPendingTx{},
+	"set_recipient":               PayloadSetRecipient{},
+	"remove_recipient":            PayloadRemoveRecipient{},
+	"list_recipient":              PayloadListRecipient{},
+	"set_account_level_rule":      PayloadSetInitiatorRule{},
+	"delete_account_level_rule":   PayloadDeleteInitiatorRule{},
+	"list_account_level_rules":    PayloadListInitiatorRules{},
+	"grant_role":                  PayloadGrantRole{},
+	"revoke_role":                 PayloadRevokeRole{},
+	"batch_status":                PayloadBatchStatus{},
+	"set_account_policy":          PayloadSetAccountPolicy{},
+	"get_account_policy":          PayloadGetAccountPolicy{},
+	"set_holidays":                PayloadSetHolidays{},
+	"list_holidays":               PayloadListHolidays{},
+}
+
+// PayloadSchema returns the json schema of the payload of request type t
+func PayloadSchema(t string) (*Schema, bool) {
+	s, ok := payloadToStruct[t]
+	if !ok {
+		return nil, false
+	}
+
+	return schemaOf(reflect.TypeOf(s)), true
+}
+
+// ValidatePayload checks pl, the Payload of a SignedPayload, against the definition of request type t: its json schema for json payloads, its protobuf message for the others
+func ValidatePayload(t string, pl []byte) error {
+	s, ok := PayloadSchema(t)
+	if !ok {
+		return errors.New("no schema for request type " + t)
+	}
+
+	if !IsJSONPayload(pl) {
+		return DecodePayload(pl, reflect.New(reflect.TypeOf(payloadToStruct[t])).Interface())
+	}
+
+	var v interface{}
+	err := json.Unmarshal(pl, &v)
+	if err != nil {
+		return err
+	}
+
+	return validate(s, v, t)
+}
+
+func validate(s *Schema, v interface{}, path string) error {
+	if v == nil {
+		if s.Nullable {
+			return nil
+		}
+		return errors.New(path + " is null")
+	}
+
+	switch s.Type {
+	case "object":
+		o, ok := v.(map[string]interface{})
+		if !ok {
+			return errors.New(path + " is not an object")
+		}
+		for _, r := range s.Required {
+			if _, ok := o[r]; !ok {
+				return errors.New(path + "." + r + " is missing")
+			}
+		}
+		for k, f := range o {
+			p, ok := s.Properties[k]
+			if !ok {
+				return errors.New(path + "." + k + " is not a known field")
+			}
+			err := validate(p, f, path+"."+k)
+			if err != nil {
+				return err
+			}
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			return errors.New(path + " is not an array")
+		}
+		for _, e := range a {
+			err := validate(s.Items, e, path+"[]")
+			if err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return errors.New(path + " is not a string")
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return errors.New(path + " is not a number")
+		}
+	case "integer":
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) {
+			return errors.New(path + " is not an integer")
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return errors.New(path + " is not a boolean")
+		}
+	}
+
+	return nil
+}
+
+// schemaOf the json schema of what encoding/json makes of type t. only the kinds found in payloads are handled
+func schemaOf(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, omitEmpty := jsonName(f)
+			if name == "-" {
+				continue
+			}
+			s.Properties[name] = schemaOf(f.Type)
+			if !omitEmpty {
+				s.Required = append(s.Required, name)
+			}
+		}
+		return s
+	case reflect.Slice:
+		// []byte is base64 encoded
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64", Nullable: true}
+		}
+		return &Schema{Type: "array", Items: schemaOf(t.Elem()), Nullable: true}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	}
+
+	panic("no json schema for " + t.String())
+}
+
+// jsonName the name encoding/json gives field f and whether it's omitted when empty
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := strings.Split(f.Tag.Get("json"), ",")
+	name := tag[0]
+	if name == "" {
+		name = f.Name
+	}
+
+	omitEmpty := false
+	for _, o := range tag[1:] {
+		if o == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty
+}
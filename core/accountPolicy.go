@@ -0,0 +1,135 @@
+package core
+
+import (
+	c "../common"
+	pb "../protos"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// PayloadSetAccountPolicy methods implementation
+type PayloadSetAccountPolicy c.PayloadSetAccountPolicy
+
+// PayloadGetAccountPolicy methods implementation
+type PayloadGetAccountPolicy c.PayloadGetAccountPolicy
+
+// Default policies of an account, i.e., what query_auth decides when no rule does
+const (
+	PolicyAllowUnlessDenied string = "allow_unless_denied"
+	PolicyDenyUnlessAllowed string = "deny_unless_allowed"
+)
+
+// DefaultAccountPolicy of accounts on which no policy was set. it's how accounts always behaved
+const DefaultAccountPolicy = PolicyAllowUnlessDenied
+
+const policyNamespace = "05"
+
+// Apply applier for setting the default policy of the account
+func (*PayloadSetAccountPolicy) Apply(pl []byte, context StateContext) error {
+	var p PayloadSetAccountPolicy
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return validationError("malformed set account policy payload", err)
+	}
+	err = checkPolicy(p.Policy)
+	if err != nil {
+		return err
+	}
+
+	a, err := encodeState(&pb.AccountPolicy{Policy: p.Policy})
+	if err != nil {
+		return err
+	}
+
+	addresses, err := context.SetState(map[string][]byte{
+		accountPolicy(p.SourceAccount): a,
+	})
+	if err != nil || len(addresses) == 0 {
+		return stateError("error setting account policy", err)
+	}
+
+	return nil
+}
+
+// Handle returns the default policy of the account. accounts on which none was set have DefaultAccountPolicy
+func (*PayloadGetAccountPolicy) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
+	var p PayloadGetAccountPolicy
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return nil, validationError("malformed get account policy payload", err)
+	}
+
+	policy, err := readAccountPolicy(p.SourceAccount, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"policy": policy,
+	}, nil
+}
+
+// WrapInTx wrap SignedPayload with PayloadSetAccountPolicy payload in a sawtooth transaction
+func (*PayloadSetAccountPolicy) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
+	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
+	if !ok {
+		return nil, authorizationError("invalid signature for set account policy transaction")
+	}
+
+	var p PayloadSetAccountPolicy
+	err := c.DecodePayload(pl.Payload, &p)
+	if err != nil {
+		return nil, validationError("malformed set account policy payload", err)
+	}
+	err = checkPolicy(p.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []string{accountPolicy(p.SourceAccount)}
+	inputs := outputs
+	dependencies := []string{}
+	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, authorizationError("signer of set account policy transaction is not authorised")
+	}
+
+	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
+}
+
+func checkPolicy(policy string) error {
+	if policy != PolicyAllowUnlessDenied && policy != PolicyDenyUnlessAllowed {
+		return validationError("unknown account policy "+policy+". expecting "+PolicyAllowUnlessDenied+" or "+PolicyDenyUnlessAllowed, nil)
+	}
+
+	return nil
+}
+
+// readAccountPolicy the default policy of sourceAccount in state. replays against a snapshot see the policy of the time
+func readAccountPolicy(sourceAccount string, state StateReader) (string, error) {
+	_, data, err := state.Read(accountPolicy(sourceAccount))
+	if err != nil {
+		return "", err
+	}
+	if len(data) == 0 {
+		return DefaultAccountPolicy, nil
+	}
+
+	var p pb.AccountPolicy
+	err = decodeState(stateAccountPolicy, data[0], &p)
+	if err != nil {
+		return "", err
+	}
+
+	return p.Policy, nil
+}
+
+// address of the default policy of the account. one leaf per account
+func accountPolicy(sourceAccount string) string {
+	root := Namespace(familyName(sourceAccount, InitiatorPermissionTag)) + policyNamespace
+	dummyString := "account policy lives here"
+	return CheckLength(root + HexdigestStr(dummyString)[:AddressLength-len(root)])
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	c "../common"
+	tpr "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// MaxBatchSize is the most requests SubmitBatch() takes. TODO the validator has limits of its own on batch size, align with its configuration
+const MaxBatchSize = 100
+
+// TxStatus status of the transaction of one request of a batch when the batch is submitted, i.e., PENDING. the batch is committed all or none: its final status, with the id and the message of the transaction rejected if it's INVALID, comes from batch_status requests or the listeners, see AddBatchListener()
+type TxStatus struct {
+	Type          string `json:"type"`
+	TransactionID string `json:"transaction_id"`
+	Status        string `json:"status"`
+}
+
+// SubmitBatch wraps the requests in transactions and submits them in one batch: they're all committed or none is, e.g., when onboarding an account. Only requests that change the state, i.e., with a Wrapper, can be batched. Every request is checked against the state, pinned once for the batch, as the requests before it leave it, see stageBatch(), and a transaction depends on the earlier ones whose outputs it reads. Like SubmitTxAsync() it returns the id of the batch right away, along with the status of every transaction, and the final status goes to done, if not nil, then to the listeners
+func SubmitBatch(pls []*c.SignedPayload, done func(status *BatchStatus)) (string, []TxStatus, error) {
+	if len(pls) == 0 {
+		return "", nil, validationError("empty batch", nil)
+	}
+	if len(pls) > MaxBatchSize {
+		return "", nil, validationError("more than "+strconv.Itoa(MaxBatchSize)+" requests in batch", nil)
+	}
+
+	state, err := PinState()
+	if err != nil {
+		return "", nil, err
+	}
+	txs, err := stageBatch(pls, state)
+	if err != nil {
+		return "", nil, err
+	}
+
+	id, err := submitAsync(txs, done)
+	if err != nil {
+		return "", nil, err
+	}
+
+	statuses := make([]TxStatus, len(txs))
+	for i, tx := range txs {
+		statuses[i] = TxStatus{Type: pls[i].Type, TransactionID: tx.HeaderSignature, Status: "PENDING"}
+	}
+
+	return id, statuses, nil
+}
+
+// stageBatch wraps the requests in transactions, each against state as the transactions before it leave it: the requests read a batchState on top of state, on which every transaction is applied as the transaction processor will apply it. so a role granted, or a rule set, earlier in the batch counts
+func stageBatch(pls []*c.SignedPayload, state StateReader) ([]*tpr.Transaction, error) {
+	staged := &batchState{base: state, changes: make(map[string][]byte)}
+
+	txs := make([]*tpr.Transaction, len(pls))
+	headers := make([]*tpr.TransactionHeader, len(pls))
+	for i, pl := range pls {
+		tx, err := wrapInBatch(pl, txs[:i], headers[:i], staged)
+		if err == nil {
+			txs[i] = tx
+			headers[i], err = txHeader(tx)
+		}
+		if err == nil {
+			err = staged.apply(headers[i], tx.Payload)
+		}
+		if err != nil {
+			// the index tells the client which request failed
+			return nil, &Error{Kind: KindOf(err), Msg: "request " + strconv.Itoa(i) + " of batch", Err: err}
+		}
+	}
+
+	return txs, nil
+}
+
+// batchState is the state as the transactions of a batch being staged leave it: the state the batch is staged on with their changes on top
+type batchState struct {
+	base    StateReader
+	changes map[string][]byte // nil for the addresses deleted
+}
+
+// Read is base.Read() with the changes on top
+func (b *batchState) Read(address string) ([]string, [][]byte, error) {
+	addresses, data, err := b.base.Read(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := make(map[string][]byte, len(addresses))
+	for i, a := range addresses {
+		m[a] = data[i]
+	}
+	for a, d := range b.changes {
+		if !strings.HasPrefix(a, address) {
+			continue
+		}
+		if d == nil {
+			delete(m, a)
+		} else {
+			m[a] = d
+		}
+	}
+
+	return readPrefix(m, address)
+}
+
+// apply the transaction with header and payload to the state, with the checks of the transaction processor, and keep its changes
+func (b *batchState) apply(header *tpr.TransactionHeader, payload []byte) error {
+	data := make(map[string][]byte)
+	for _, in := range header.Inputs {
+		addresses, d, err := b.Read(in)
+		if err != nil {
+			return err
+		}
+		for i, a := range addresses {
+			data[a] = d[i]
+		}
+	}
+	before := make(map[string][]byte, len(data))
+	for a, d := range data {
+		before[a] = d
+	}
+
+	err := ApplyTransaction(header, payload, &memoryContext{data: data, inputs: header.Inputs, outputs: header.Outputs})
+	if err != nil {
+		return err
+	}
+
+	for a, d := range data {
+		if old, ok := before[a]; !ok || !bytes.Equal(old, d) {
+			b.changes[a] = d
+		}
+	}
+	for a := range before {
+		if _, ok := data[a]; !ok {
+			b.changes[a] = nil
+		}
+	}
+
+	return nil
+}
+
+// wrapInBatch wraps pl in a transaction, checked against state, that depends on the transactions, earlier in the batch, it reads the outputs of
+func wrapInBatch(pl *c.SignedPayload, earlier []*tpr.Transaction, earlierHeaders []*tpr.TransactionHeader, state StateReader) (*tpr.Transaction, error) {
+	// requests with a Handle(), e.g., add_sig_tx, do more than submitting their transaction: the bank signs it, or acts on its outcome
+	pc, ok := LookupPayload(pl.Type)
+	if !ok || pc.Wrapper == nil || pc.Querier != nil {
+		return nil, validationError(pl.Type+" requests cannot be batched", nil)
+	}
+	err := CheckPayload(pl)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := pc.Wrapper().WrapInTx(pl, state)
+	if err != nil {
+		return nil, err
+	}
+	header, err := txHeader(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := header.Dependencies
+	for j, h := range earlierHeaders {
+		if overlaps(header.Inputs, h.Outputs) && !contains(dependencies, earlier[j].HeaderSignature) {
+			dependencies = append(dependencies, earlier[j].HeaderSignature)
+		}
+	}
+	if len(dependencies) == len(header.Dependencies) {
+		return tx, nil
+	}
+
+	// the header changes and so does its signature, i.e., the id of the transaction
+	header.Dependencies = dependencies
+	return signTransaction(header, tx.Payload)
+}
+
+// overlaps tells whether an address, or address prefix, of inputs and one of outputs have addresses in common
+func overlaps(inputs, outputs []string) bool {
+	for _, i := range inputs {
+		for _, o := range outputs {
+			if strings.HasPrefix(i, o) || strings.HasPrefix(o, i) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	c "../common"
+)
+
+func TestWrapInBatchRejectsRequestsWithAHandler(t *testing.T) {
+	payloads := map[string]interface{}{
+		"add_sig_tx":           &c.PayloadAddSigTx{SourceAccount: testAccount, TransactionID: strings.Repeat("0", pendingTxUIDLength), Initiator: "ID12345", Signature: []byte{1}, PubKey: []byte{2}},
+		"query_auth":           &c.PayloadQueryAuth{SourceAccount: testAccount, Initiator: "ID12345", Amount: 10},
+		"list_initiator_rules": &c.PayloadListInitiatorRules{SourceAccount: testAccount, Initiator: "ID12345"},
+	}
+	for typ, p := range payloads {
+		pl, err := c.EncodePayload(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the signature doesn't matter: the request is turned down before it's checked
+		_, err = wrapInBatch(&c.SignedPayload{Version: c.PayloadVersion, Type: typ, SourceAccount: testAccount, Payload: pl}, nil, nil, nil)
+		if KindOf(err) != KindValidation {
+			t.Errorf("wrapInBatch() of a %s request = %v, want a validation error", typ, err)
+		}
+	}
+}
+
+// a role granted earlier in the batch counts for the requests after it, which depend on its transaction
+func TestSubmitBatch(t *testing.T) {
+	defer useBankKeys(t)()
+	s, restore := useMemoryState()
+	defer restore()
+	bankPubKey, bank := GetBankAuthTools()
+	setter, setterPubKey := newSigner()
+
+	grant := signed(t, "grant_role", &c.PayloadGrantRole{SourceAccount: testAccount, PermissionTag: RecipientPermissionTag, PubKey: hex.EncodeToString(setterPubKey), Role: RoleRuleSetter}, bank, bankPubKey.AsBytes())
+	recipient := signed(t, "set_recipient", &c.PayloadSetRecipient{SourceAccount: testAccount, Recipient: "bob", DestAccount: "12345678"}, setter, setterPubKey)
+
+	// the role isn't granted yet
+	_, _, err := SubmitBatch([]*c.SignedPayload{recipient, grant}, nil)
+	if KindOf(err) != KindAuthorization || !strings.Contains(err.Error(), "request 0 of batch") {
+		t.Errorf("SubmitBatch() of a request before the grant it needs = %v, want an authorization error on request 0", err)
+	}
+
+	done := make(chan *BatchStatus, 1)
+	id, statuses, err := SubmitBatch([]*c.SignedPayload{grant, recipient}, func(status *BatchStatus) { done <- status })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].Type != "grant_role" || statuses[1].Status != "PENDING" {
+		t.Errorf("SubmitBatch() = %+v", statuses)
+	}
+	if status := <-done; status.ID != id || status.Status != "COMMITTED" {
+		t.Fatalf("batch = %+v", status)
+	}
+	if a, _, _ := s.Read(recipientAccount(recipientRootStateAddress(testAccount), "bob", "12345678")); len(a) != 1 {
+		t.Error("recipient of the batch not set")
+	}
+}
+
+func TestStageBatchDependencies(t *testing.T) {
+	defer useBankKeys(t)()
+	s := NewMemoryState()
+	bankPubKey, bank := GetBankAuthTools()
+	setter, setterPubKey := newSigner()
+
+	pls := []*c.SignedPayload{
+		signed(t, "set_recipient", &c.PayloadSetRecipient{SourceAccount: testAccount, Recipient: "alice", DestAccount: "12345678"}, bank, bankPubKey.AsBytes()),
+		signed(t, "grant_role", &c.PayloadGrantRole{SourceAccount: testAccount, PermissionTag: RecipientPermissionTag, PubKey: hex.EncodeToString(setterPubKey), Role: RoleRuleSetter}, bank, bankPubKey.AsBytes()),
+		signed(t, "set_recipient", &c.PayloadSetRecipient{SourceAccount: testAccount, Recipient: "bob", DestAccount: "12345678"}, setter, setterPubKey),
+	}
+	txs, err := stageBatch(pls, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// staging leaves the state alone
+	if a, _, _ := s.Read(Namespace(familyName(testAccount, ""))); len(a) != 0 {
+		t.Errorf("stageBatch() wrote %v", a)
+	}
+
+	// the set_recipient of the setter reads the role granted before it, not the recipient set first
+	var deps [][]string
+	for _, tx := range txs {
+		header, err := txHeader(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deps = append(deps, header.Dependencies)
+	}
+	if len(deps[0]) != 0 || len(deps[1]) != 0 || len(deps[2]) != 1 || deps[2][0] != txs[1].HeaderSignature {
+		t.Errorf("dependencies = %v, want the last transaction to depend on the grant", deps)
+	}
+
+	// the role is revoked once the grant is committed, before the rest of the batch is: the rest is rejected as a whole, the recipient set first included
+	revoke, err := c.EncodePayload(&c.PayloadRevokeRole{SourceAccount: testAccount, PermissionTag: RecipientPermissionTag, PubKey: hex.EncodeToString(setterPubKey), Role: RoleRuleSetter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := submit(t, s, txs[1]); st.Status != "COMMITTED" {
+		t.Fatalf("grant = %+v", st)
+	}
+	if err := (&PayloadRevokeRole{}).Apply(revoke, s); err != nil {
+		t.Fatal(err)
+	}
+	if st := submit(t, s, txs[0], txs[2]); st.Status != "INVALID" || len(st.InvalidTransactions) != 1 || st.InvalidTransactions[0].ID != txs[2].HeaderSignature {
+		t.Errorf("batch after the revoke = %+v, want its last transaction rejected", st)
+	}
+	if a, _, _ := s.Read(recipientAccount(recipientRootStateAddress(testAccount), "alice", "12345678")); len(a) != 0 {
+		t.Error("recipient of a rejected batch set")
+	}
+}
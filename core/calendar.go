@@ -0,0 +1,188 @@
+package core
+
+import (
+	"time"
+
+	c "../common"
+	pb "../protos"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// PayloadSetHolidays methods implementation
+type PayloadSetHolidays c.PayloadSetHolidays
+
+// PayloadListHolidays methods implementation
+type PayloadListHolidays c.PayloadListHolidays
+
+// DateFormat of holidays, days in common.RuleLocation. also a format govaluate takes date literals in, e.g., Date == '2026-12-25'
+const DateFormat = "2006-01-02"
+
+const calendarNamespace = "06"
+
+// Apply applier for replacing the holiday calendar of the account
+func (*PayloadSetHolidays) Apply(pl []byte, context StateContext) error {
+	var p PayloadSetHolidays
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return validationError("malformed set holidays payload", err)
+	}
+	err = checkHolidays(p.Holidays)
+	if err != nil {
+		return err
+	}
+
+	h, err := encodeState(&pb.HolidayCalendar{Dates: p.Holidays})
+	if err != nil {
+		return err
+	}
+
+	addresses, err := context.SetState(map[string][]byte{
+		holidayCalendar(p.SourceAccount): h,
+	})
+	if err != nil || len(addresses) == 0 {
+		return stateError("error setting holidays", err)
+	}
+
+	return nil
+}
+
+// Handle list the holidays of the account
+func (*PayloadListHolidays) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
+	var p PayloadListHolidays
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return nil, validationError("malformed list holidays payload", err)
+	}
+
+	holidays, err := readHolidays(p.SourceAccount, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"holidays": holidays,
+	}, nil
+}
+
+// WrapInTx wrap SignedPayload with PayloadSetHolidays payload in a sawtooth transaction
+func (*PayloadSetHolidays) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
+	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
+	if !ok {
+		return nil, authorizationError("invalid signature for set holidays transaction")
+	}
+
+	var p PayloadSetHolidays
+	err := c.DecodePayload(pl.Payload, &p)
+	if err != nil {
+		return nil, validationError("malformed set holidays payload", err)
+	}
+	err = checkHolidays(p.Holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []string{holidayCalendar(p.SourceAccount)}
+	inputs := outputs
+	dependencies := []string{}
+	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, authorizationError("signer of set holidays transaction is not authorised")
+	}
+
+	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
+}
+
+func checkHolidays(holidays []string) error {
+	for _, h := range holidays {
+		_, err := time.Parse(DateFormat, h)
+		if err != nil {
+			return validationError("malformed holiday "+h+". expecting yyyy-mm-dd", err)
+		}
+	}
+
+	return nil
+}
+
+// readHolidays the holiday calendar of sourceAccount. empty if none was set
+func readHolidays(sourceAccount string, state StateReader) ([]string, error) {
+	_, data, err := state.Read(holidayCalendar(sourceAccount))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+
+	var calendar pb.HolidayCalendar
+	err = decodeState(stateHolidayCalendar, data[0], &calendar)
+	if err != nil {
+		return nil, err
+	}
+
+	return calendar.Dates, nil
+}
+
+// bindClock binds the variables of common.BindClock to t, the time of the banking transaction in m, unless they are already bound. the day and the hour are those of t in common.RuleLocation, like the dates in rules
+func bindClock(m map[string]interface{}, t time.Time) {
+	if _, ok := m["Now"]; ok {
+		return
+	}
+
+	t = t.In(c.RuleLocation)
+	y, mo, d := t.Date()
+	m["Now"] = float64(t.Unix())
+	m["Date"] = float64(time.Date(y, mo, d, 0, 0, 0, 0, c.RuleLocation).Unix())
+	m["Hour"] = float64(t.Hour())
+	m["Weekday"] = t.Weekday().String()
+}
+
+// clockOf the time bindClock() bound m to
+func clockOf(m map[string]interface{}) time.Time {
+	now, ok := m["Now"].(float64)
+	if !ok {
+		return time.Now()
+	}
+
+	return time.Unix(int64(now), 0)
+}
+
+// isHoliday returns IsHoliday() for rules evaluated on banking transactions on sourceAccount at time now: whether the day is in the holiday calendar of the account in state
+func isHoliday(sourceAccount string, now time.Time, state StateReader) func(args ...interface{}) (interface{}, error) {
+	return func(args ...interface{}) (interface{}, error) {
+		holidays, err := readHolidays(sourceAccount, state)
+		if err != nil {
+			return nil, err
+		}
+
+		return contains(holidays, now.In(c.RuleLocation).Format(DateFormat)), nil
+	}
+}
+
+// isBusinessDay returns IsBusinessDay() for rules evaluated on banking transactions on sourceAccount at time now: neither a weekend nor a holiday of the account
+func isBusinessDay(sourceAccount string, now time.Time, state StateReader) func(args ...interface{}) (interface{}, error) {
+	holiday := isHoliday(sourceAccount, now, state)
+	return func(args ...interface{}) (interface{}, error) {
+		switch now.In(c.RuleLocation).Weekday() {
+		case time.Saturday, time.Sunday:
+			return false, nil
+		}
+
+		h, err := holiday()
+		if err != nil {
+			return nil, err
+		}
+
+		return !h.(bool), nil
+	}
+}
+
+// address of the holiday calendar of the account. one leaf per account
+func holidayCalendar(sourceAccount string) string {
+	root := Namespace(familyName(sourceAccount, InitiatorPermissionTag)) + calendarNamespace
+	dummyString := "holiday calendar lives here"
+	return CheckLength(root + HexdigestStr(dummyString)[:AddressLength-len(root)])
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	c "../common"
+)
+
+func TestCalendar(t *testing.T) {
+	s := NewMemoryState()
+	set := func(holidays ...string) error {
+		pl, err := c.EncodePayload(c.PayloadSetHolidays{SourceAccount: testAccount, Holidays: holidays})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return (&PayloadSetHolidays{}).Apply(pl, s)
+	}
+
+	if err := set("25/12/2026"); KindOf(err) != KindValidation {
+		t.Errorf("set_holidays of a malformed date = %v, want a validation error", err)
+	}
+	if err := set("2026-12-25"); err != nil {
+		t.Fatal(err)
+	}
+	pl, err := c.EncodePayload(c.PayloadListHolidays{SourceAccount: testAccount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err := (&PayloadListHolidays{}).Handle(pl, s)
+	if err != nil || !reflect.DeepEqual(ret["holidays"], []string{"2026-12-25"}) {
+		t.Errorf("list_holidays = %v, %v", ret, err)
+	}
+
+	// days are those of common.RuleLocation, whatever the time zone of the machine. one behind UTC tells them apart
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer func(l *time.Location) { c.RuleLocation = l }(c.RuleLocation)
+	c.RuleLocation = loc
+
+	tests := []struct {
+		name        string
+		t           time.Time
+		holiday     bool
+		businessDay bool
+	}{
+		{"holiday", time.Date(2026, 12, 25, 0, 30, 0, 0, c.RuleLocation), true, false},
+		{"the evening before", time.Date(2026, 12, 24, 23, 30, 0, 0, c.RuleLocation), false, true},
+		{"saturday", time.Date(2026, 12, 26, 12, 0, 0, 0, c.RuleLocation), false, false},
+		{"monday", time.Date(2026, 12, 28, 12, 0, 0, 0, c.RuleLocation), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := map[string]interface{}{"SourceAccount": testAccount}
+			bindClock(m, tt.t)
+
+			for rule, want := range map[string]bool{
+				"IsHoliday() ? 'deny' : 'nil'":                                  tt.holiday,
+				"IsBusinessDay() ? 'deny' : 'nil'":                              tt.businessDay,
+				"Weekday == '" + tt.t.Weekday().String() + "' ? 'deny' : 'nil'": true,
+				"Hour == " + tt.t.Format("15") + " ? 'deny' : 'nil'":            true,
+				"(Date == '" + tt.t.Format(DateFormat) + "') ? 'deny' : 'nil'":  true,
+			} {
+				r, err := NewRule(rule, "hash")
+				if err != nil {
+					t.Fatal(err)
+				}
+				ev, err := r.Evaluate(m, s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if (ev == "deny") != want {
+					t.Errorf("%s = %v, want %v", rule, ev, want)
+				}
+			}
+		})
+	}
+}
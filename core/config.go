@@ -1,11 +1,18 @@
 package core
 
+import (
+	c "../common"
+)
+
 // Internal Constants
 const (
 	EncryptionAlgoName string = "secp256k1"
-	FamilyVersion      string = "0.1"
+	FamilyVersion      string = c.PayloadVersion // the version of the transactions the bank creates, e.g., set_pending_tx
 )
 
+// FamilyVersions the transaction processor handles. Note: the state format is versioned separately, see StateVersion, as all versions read and write the same state
+var FamilyVersions = []string{c.LegacyPayloadVersion, c.JSONPayloadVersion, FamilyVersion}
+
 // Permission tags used in transaction family names. With these sys admin can configure role level permissions as opposed to account level only. Important Note: as of this version, they must remain empty strings.
 const (
 	RecipientPermissionTag string = ""
@@ -24,6 +31,44 @@ var typeToPermissionTag = map[string]string{
 	"delete_initiator_pub_keys":   InitiatorPermissionTag,
 	"set_account_level_rule":      InitiatorPermissionTag,
 	"delete_account_level_rule":   InitiatorPermissionTag,
+	"set_account_policy":          InitiatorPermissionTag,
+	"set_holidays":                InitiatorPermissionTag,
+	"purge_expired_rules":         InitiatorPermissionTag,
+}
+
+// Roles held by public keys on an account, per permission tag. Granted and revoked by bank admins. The bank itself is admin on every account
+const (
+	RoleBankAdmin  string = "bank_admin"
+	RoleRuleSetter string = "rule_setter"
+	RoleTransactor string = "transactor"
+	RoleSigner     string = "signer"
+)
+
+// the roles that can be granted and revoked
+var allRoles = []string{RoleBankAdmin, RoleRuleSetter, RoleTransactor, RoleSigner}
+
+// the role the signer of a payload must hold. Note if payload type is not a key here, e.g., queries, no role is needed. bank admins can submit every payload
+var payloadToRole = map[string]string{
+	"set_recipient":               RoleRuleSetter,
+	"remove_recipient":            RoleRuleSetter,
+	"set_initiator_rule":          RoleRuleSetter,
+	"delete_initiator_rule":       RoleRuleSetter,
+	"add_initiator_to_group":      RoleRuleSetter,
+	"remove_initiator_from_group": RoleRuleSetter,
+	"set_account_level_rule":      RoleRuleSetter,
+	"delete_account_level_rule":   RoleRuleSetter,
+	"set_account_policy":          RoleRuleSetter,
+	"set_holidays":                RoleRuleSetter,
+	"set_initiator_pub_keys":      RoleBankAdmin,
+	"delete_initiator_pub_keys":   RoleBankAdmin,
+	"grant_role":                  RoleBankAdmin,
+	"revoke_role":                 RoleBankAdmin,
+	"query_auth":                  RoleTransactor,
+	"close_pending_tx":            RoleTransactor,
+	"add_sig_tx":                  RoleSigner,
+	"set_pending_tx":              RoleBankAdmin, // only the bank sets pending txs, records spend and purges expired rules
+	"record_spend":                RoleBankAdmin,
+	"purge_expired_rules":         RoleBankAdmin,
 }
 
 // address calculation related constants
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	v "../verification"
+)
+
+// names of the SMT constants used in consistency checks. the rule hash follows the prefix
+const (
+	outcomePrefix   = "o_" // what the rule returns
+	predicatePrefix = "p_" // the rule returns something other than 'nil'
+	firesPrefix     = "f_" // the rule fires: it returns something other than 'nil' and no specific rule overrides it
+	decidesPrefix   = "d_" // the rule fires and, unless it's final, no rule of a higher priority does, i.e., it takes part in the decision
+	silentPrefix    = "n_" // the rule does not take part in the decision with a deny
+)
+
+// checkConsistency checks newRule, about to be set on initiator, against the rules already in force for initiator: its own, those of its groups and the account level rules. An error naming the rule hashes in the unsat core is returned if newRule can never fire or if it makes dead another rule, i.e., a rule that could fire before newRule was set can't anymore. newRule must translate to SMT. rules in force that don't, set before there were checks, are left out of the check
+func checkConsistency(sourceAccount, initiator string, newRule ARule, state StateReader) error {
+	irs, grs, _, err := initiatorAndGroupRules(sourceAccount, initiator, state)
+	if err != nil {
+		return err
+	}
+
+	// setting a rule that already exists overwrites it. expired rules are never evaluated again
+	now := time.Now()
+	spec := make([]ARule, 0, len(irs))
+	for _, r := range irs {
+		if r.RuleHash != newRule.RuleHash && r.Status(now) != RuleExpired {
+			spec = append(spec, r)
+		}
+	}
+	gen := make([]ARule, 0, len(grs))
+	for _, r := range grs {
+		if r.Status(now) != RuleExpired {
+			gen = append(gen, r)
+		}
+	}
+
+	// first the rule on its own
+	_, err = v.Translate(newRule.Rule)
+	if err != nil {
+		return validationError("rule "+newRule.RuleHash+" cannot be checked for consistency", err)
+	}
+	res, err := v.Analyze([]v.Rule{{Hash: newRule.RuleHash, Rule: newRule.Rule}})
+	if err != nil {
+		return internalError("cannot check consistency of rule "+newRule.RuleHash, err)
+	}
+	if res.Status == v.Unsat {
+		return inconsistencyError("rule "+newRule.RuleHash+" can never fire", res.Core)
+	}
+
+	// now against the rules in force
+	before, err := deadRules(gen, spec)
+	if err != nil {
+		return err
+	}
+	after, err := deadRules(gen, append(spec, newRule))
+	if err != nil {
+		return err
+	}
+
+	if core, ok := after[newRule.RuleHash]; ok {
+		return inconsistencyError("rule "+newRule.RuleHash+" can never fire given the rules in force", core)
+	}
+
+	hashes := make([]string, 0, len(after))
+	for h := range after {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes)
+	for _, h := range hashes {
+		if _, ok := before[h]; !ok {
+			return inconsistencyError("rule "+newRule.RuleHash+" makes rule "+h+" dead", after[h])
+		}
+	}
+
+	return nil
+}
+
+// deadRules returns the rules in gen and spec that can never take part in a decision, each mapped to the hashes of the rules in the unsat core explaining why. As in evaluateRules(), a rule doesn't when it returns 'nil', when a specific rule overrides it (see shadows()), when a rule of a higher priority fires unless it's final and, unless it may deny itself, when another rule taking part in the decision denies. rules are only held against the rules in force at the same time
+func deadRules(gen, spec []ARule) (map[string][]string, error) {
+	ret := make(map[string][]string)
+
+	exprs := make(map[string]string)
+	denies := make([]string, 0)
+	byHash := make(map[string]ARule)
+	translated := func(r ARule) bool {
+		t, err := v.Translate(r.Rule)
+		if err != nil {
+			return false
+		}
+		exprs[outcomePrefix+r.RuleHash] = r.Rule
+		byHash[r.RuleHash] = r
+		if t.MayDeny {
+			denies = append(denies, r.RuleHash)
+		}
+
+		return true
+	}
+
+	rules := make([]string, 0)
+	overriders := make(map[string][]string)
+	for _, g := range gen {
+		if !translated(g) {
+			continue
+		}
+		rules = append(rules, g.RuleHash)
+		for _, s := range spec {
+			if shadows(&s, &g) {
+				overriders[g.RuleHash] = append(overriders[g.RuleHash], s.RuleHash)
+			}
+		}
+	}
+	for _, s := range spec {
+		if translated(s) {
+			rules = append(rules, s.RuleHash)
+		}
+	}
+
+	// a rule is outranked when one of a higher priority, in force at the same time, fires
+	outrankers := make(map[string][]string)
+	for _, h := range rules {
+		r := byHash[h]
+		if r.Final() {
+			continue
+		}
+		for _, o := range rules {
+			if hr := byHash[o]; hr.Priority > r.Priority && coexist(&hr, &r) {
+				outrankers[h] = append(outrankers[h], o)
+			}
+		}
+	}
+
+	program, err := v.CreateNamedSMTprogram(exprs)
+	if err != nil {
+		return nil, internalError("cannot build consistency check", err)
+	}
+	for _, h := range rules {
+		program += v.DefineBool(predicatePrefix+h, v.Fires(outcomePrefix+h))
+	}
+	for _, h := range rules {
+		program += v.DefineBool(firesPrefix+h, andNot(predicatePrefix+h, predicatePrefix, overriders[h]))
+	}
+	for _, h := range rules {
+		program += v.DefineBool(decidesPrefix+h, andNot(firesPrefix+h, firesPrefix, outrankers[h]))
+		program += v.DefineBool(silentPrefix+h, "(not (and "+decidesPrefix+h+" "+v.Denies(outcomePrefix+h)+"))")
+	}
+
+	ctx := v.NewContext()
+	defer ctx.Close()
+	for _, h := range rules {
+		assumptions := []string{decidesPrefix + h}
+		if !contains(denies, h) {
+			r := byHash[h]
+			for _, d := range denies {
+				if dr := byHash[d]; coexist(&dr, &r) {
+					assumptions = append(assumptions, silentPrefix+d)
+				}
+			}
+		}
+
+		res, err := ctx.Check(program, assumptions)
+		if err != nil {
+			return nil, internalError("cannot check consistency of rule "+h, err)
+		}
+		if res.Status != v.Unsat {
+			continue
+		}
+
+		m := make(map[string]bool)
+		for _, name := range res.Core {
+			hash := name[len(decidesPrefix):] // Note: all prefixes have the same length
+			m[hash] = true
+			if strings.HasPrefix(name, decidesPrefix) {
+				// the overrides and the rules of a higher priority are part of the definition of the rule deciding, not assumptions, so they don't show up in the core on their own
+				for _, o := range append(overriders[hash], outrankers[hash]...) {
+					m[o] = true
+				}
+			}
+		}
+		for hash := range m {
+			ret[h] = append(ret[h], hash)
+		}
+		sort.Strings(ret[h])
+	}
+
+	return ret, nil
+}
+
+// andNot the SMT conjunction of name and of the negation of the constants prefix followed by the hashes
+func andNot(name, prefix string, hashes []string) string {
+	if len(hashes) == 0 {
+		return name
+	}
+
+	ret := "(and " + name
+	for _, h := range hashes {
+		ret += " (not " + prefix + h + ")"
+	}
+
+	return ret + ")"
+}
+
+func contains(a []string, s string) bool {
+	for _, item := range a {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func inconsistencyError(msg string, core []string) error {
+	return validationError(msg+"; unsat core: "+strings.Join(core, ","), nil)
+}
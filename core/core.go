@@ -1,31 +1,136 @@
 package core
 
 import (
-	"reflect"
+	"errors"
+
+	c "../common"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
 )
 
-// PayloadRegistry map strings to payload types
-var PayloadRegistry = map[string]reflect.Type{
-	"set_initiator_rule":          reflect.TypeOf(&PayloadSetInitiatorRule{}),
-	"delete_initiator_rule":       reflect.TypeOf(&PayloadDeleteInitiatorRule{}),
-	"list_initiator_rules":        reflect.TypeOf(&PayloadListInitiatorRules{}),
-	"add_initiator_to_group":      reflect.TypeOf(&PayloadAddInitiatorToGroup{}),
-	"remove_initiator_from_group": reflect.TypeOf(&PayloadRemoveInitiatorFromGroup{}),
-	"list_initiator_groups":       reflect.TypeOf(&PayloadListInitiatorGroups{}),
-	"set_initiator_pub_keys":      reflect.TypeOf(&PayloadSetInitiatorPubKeys{}),
-	"delete_initiator_pub_keys":   reflect.TypeOf(&PayloadDeleteInitiatorPubKeys{}),
-	"list_initiator_pub_keys":     reflect.TypeOf(&PayloadListInitiatorPubKeys{}),
-	"query_auth":                  reflect.TypeOf(&PayloadQueryAuth{}),
-	"set_pending_tx":              reflect.TypeOf(&PayloadSetPendingTx{}),
-	"close_pending_tx":            reflect.TypeOf(&PayloadClosePendingTx{}),
-	"add_sig_tx":                  reflect.TypeOf(&PayloadAddSigTx{}),
-	"list_pending_tx":             reflect.TypeOf(&PayloadListPendingTx{}),
-	"set_recipient":               reflect.TypeOf(&PayloadSetRecipient{}),
-	"remove_recipient":            reflect.TypeOf(&PayloadRemoveRecipient{}),
-	"list_recipient":              reflect.TypeOf(&PayloadListRecipient{}),
-	"set_account_level_rule":      reflect.TypeOf(&PayloadSetInitiatorRule{}),
-	"delete_account_level_rule":   reflect.TypeOf(&PayloadDeleteInitiatorRule{}),
-	"list_account_level_rules":    reflect.TypeOf(&PayloadListInitiatorRules{}),
+// Querier is implemented by payloads that query the state without changing it, e.g., list_recipient. query_auth is one too: it might submit a set_pending_tx transaction on top
+type Querier interface {
+	Handle(pl []byte, state StateReader) (map[string]interface{}, error)
+}
+
+// Wrapper is implemented by payloads that change the state: the lambda wraps them in a transaction and submits it to the validator
+type Wrapper interface {
+	WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error)
+}
+
+// Applier is implemented by every payload that ends up in a transaction, i.e., every Wrapper plus the payloads only the bank creates, e.g., set_pending_tx. the transaction processor calls Apply()
+type Applier interface {
+	Apply(pl []byte, context StateContext) error
+}
+
+// PayloadConstructors build the payload of a request type as the interfaces it implements. nil for the ones it doesn't
+type PayloadConstructors struct {
+	Querier func() Querier
+	Wrapper func() Wrapper
+	Applier func() Applier
+}
+
+// payloadRegistry map request types to the constructors of their payloads. Use RegisterPayload() to add to it, LookupPayload() to read it
+var payloadRegistry = make(map[string]PayloadConstructors)
+
+func init() {
+	RegisterPayload("set_initiator_rule", func() interface{} { return &PayloadSetInitiatorRule{} })
+	RegisterPayload("delete_initiator_rule", func() interface{} { return &PayloadDeleteInitiatorRule{} })
+	RegisterPayload("list_initiator_rules", func() interface{} { return &PayloadListInitiatorRules{} })
+	RegisterPayload("add_initiator_to_group", func() interface{} { return &PayloadAddInitiatorToGroup{} })
+	RegisterPayload("remove_initiator_from_group", func() interface{} { return &PayloadRemoveInitiatorFromGroup{} })
+	RegisterPayload("list_initiator_groups", func() interface{} { return &PayloadListInitiatorGroups{} })
+	RegisterPayload("set_initiator_pub_keys", func() interface{} { return &PayloadSetInitiatorPubKeys{} })
+	RegisterPayload("delete_initiator_pub_keys", func() interface{} { return &PayloadDeleteInitiatorPubKeys{} })
+	RegisterPayload("list_initiator_pub_keys", func() interface{} { return &PayloadListInitiatorPubKeys{} })
+	RegisterPayload("query_auth", func() interface{} { return &PayloadQueryAuth{} })
+	RegisterPayload("simulate_auth", func() interface{} { return &PayloadSimulateAuth{} })
+	RegisterPayload("set_pending_tx", func() interface{} { return &PayloadSetPendingTx{} })
+	RegisterPayload("record_spend", func() interface{} { return &PayloadRecordSpend{} })
+	RegisterPayload("purge_expired_rules", func() interface{} { return &PayloadPurgeExpiredRules{} })
+	RegisterPayload("close_pending_tx", func() interface{} { return &PayloadClosePendingTx{} })
+	RegisterPayload("add_sig_tx", func() interface{} { return &PayloadAddSigTx{} })
+	RegisterPayload("list_pending_tx", func() interface{} { return &PayloadListPendingTx{} })
+	RegisterPayload("set_recipient", func() interface{} { return &PayloadSetRecipient{} })
+	RegisterPayload("remove_recipient", func() interface{} { return &PayloadRemoveRecipient{} })
+	RegisterPayload("list_recipient", func() interface{} { return &PayloadListRecipient{} })
+	RegisterPayload("set_account_level_rule", func() interface{} { return &PayloadSetInitiatorRule{} })
+	RegisterPayload("delete_account_level_rule", func() interface{} { return &PayloadDeleteInitiatorRule{} })
+	RegisterPayload("list_account_level_rules", func() interface{} { return &PayloadListInitiatorRules{} })
+	RegisterPayload("grant_role", func() interface{} { return &PayloadGrantRole{} })
+	RegisterPayload("revoke_role", func() interface{} { return &PayloadRevokeRole{} })
+	RegisterPayload("batch_status", func() interface{} { return &PayloadBatchStatus{} })
+	RegisterPayload("set_account_policy", func() interface{} { return &PayloadSetAccountPolicy{} })
+	RegisterPayload("get_account_policy", func() interface{} { return &PayloadGetAccountPolicy{} })
+	RegisterPayload("set_holidays", func() interface{} { return &PayloadSetHolidays{} })
+	RegisterPayload("list_holidays", func() interface{} { return &PayloadListHolidays{} })
+
+	err := checkPayloadRegistry(c.PayloadTypes())
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterPayload makes request type t available to the client, the lambda and the transaction processor. newPayload returns a new (pointer to a) payload of the type. the payload must implement Querier or Applier, and Applier if it implements Wrapper. Registering the same type twice panics
+func RegisterPayload(t string, newPayload func() interface{}) {
+	if _, ok := payloadRegistry[t]; ok {
+		panic("payload " + t + " registered twice")
+	}
+
+	var pc PayloadConstructors
+	p := newPayload()
+	if _, ok := p.(Querier); ok {
+		pc.Querier = func() Querier { return newPayload().(Querier) }
+	}
+	if _, ok := p.(Wrapper); ok {
+		pc.Wrapper = func() Wrapper { return newPayload().(Wrapper) }
+	}
+	if _, ok := p.(Applier); ok {
+		pc.Applier = func() Applier { return newPayload().(Applier) }
+	}
+
+	if pc.Querier == nil && pc.Applier == nil {
+		panic("payload " + t + " has neither Handle() nor Apply() method")
+	}
+	if pc.Wrapper != nil && pc.Applier == nil {
+		panic("payload " + t + " must have Apply() method in addition to WrapInTx() method")
+	}
+
+	payloadRegistry[t] = pc
+}
+
+// LookupPayload returns the constructors registered for request type t
+func LookupPayload(t string) (PayloadConstructors, bool) {
+	pc, ok := payloadRegistry[t]
+	return pc, ok
+}
+
+// checkPayloadRegistry every request the client can create must be served as its kind says, see common.RequestKind(), and have a schema
+func checkPayloadRegistry(types []string) error {
+	for _, t := range types {
+		pc, ok := payloadRegistry[t]
+		if !ok {
+			return errors.New("request type " + t + " has no payload in core")
+		}
+		kind, _ := c.RequestKind(t)
+		switch {
+		case pc.Querier != nil && pc.Wrapper != nil:
+			ok = kind == c.RequestHandledTx
+		case pc.Querier != nil:
+			ok = kind == c.RequestQuery
+		case pc.Wrapper != nil:
+			ok = kind == c.RequestTx
+		default:
+			return errors.New("request type " + t + " has neither Handle() nor WrapInTx() method")
+		}
+		if !ok {
+			return errors.New("request type " + t + " is not served as a " + kind + " request")
+		}
+		if _, ok := c.PayloadSchema(t); !ok {
+			return errors.New("request type " + t + " has no schema")
+		}
+	}
+
+	return nil
 }
 
 // FamilyName returns family name from source account and payload type
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	c "../common"
+)
+
+// CoreBanking is the bank's core banking system (CBS), the system of record for accounts. Rules read balances from it and the banking transactions we execute, i.e., pending transactions once all their signatures are in, are posted to it. a query_auth is only a question: the bank posts what it executes after an allow itself
+type CoreBanking interface {
+	GetBalance(account string) (float64, error) // ErrUnknownAccount if the core banking system doesn't hold account
+
+	GetHistory(account string, since time.Time) ([]BankingTransaction, error) // most recent last
+	PostTransaction(tx BankingTransaction) error
+}
+
+// BankingTransaction is a transaction on an account as the core banking system sees it
+type BankingTransaction struct {
+	SourceAccount string  `json:"source_account"`
+	DestAccount   string  `json:"dest_account"`
+	Recipient     string  `json:"recipient"`
+	Action        string  `json:"action"`
+	Amount        float64 `json:"amount"`
+	Time          int64   `json:"time"` // unix time
+}
+
+// the core banking system in use. nil until SetCoreBanking() is called: Balance is not filled in then and rules using it can't be evaluated
+var coreBanking CoreBanking
+
+// ErrUnknownAccount is returned by a CoreBanking for accounts it doesn't hold. rules using Balance can't be evaluated for those: the query fails with a not found error naming the account
+var ErrUnknownAccount = errors.New("unknown account")
+
+// SetCoreBanking plugs in the core banking system
+func SetCoreBanking(cbs CoreBanking) {
+	coreBanking = cbs
+}
+
+// fills in the rule variables that come from the core banking system, if any, for the banking transaction in m. the core banking system is only asked when one of rules uses them
+func bindCoreBanking(m map[string]interface{}, rules []ARule) error {
+	if coreBanking == nil {
+		return nil
+	}
+	if _, ok := m["Balance"]; ok {
+		return nil
+	}
+	sourceAccount, ok := m["SourceAccount"].(string)
+	if !ok || !usesBinding(rules, c.BindCoreBanking) {
+		return nil
+	}
+
+	balance, err := coreBanking.GetBalance(sourceAccount)
+	if err == ErrUnknownAccount {
+		return notFoundError("account " + sourceAccount + " unknown to the core banking system")
+	}
+	if err != nil {
+		return stateError("cannot get balance from core banking system", err)
+	}
+	m["Balance"] = balance
+
+	return nil
+}
+
+// posts a banking transaction we executed to the core banking system, if any
+func postToCoreBanking(q *PayloadQueryAuth) error {
+	if coreBanking == nil {
+		return nil
+	}
+
+	err := coreBanking.PostTransaction(BankingTransaction{
+		SourceAccount: q.SourceAccount,
+		DestAccount:   q.DestAccount,
+		Recipient:     q.Recipient,
+		Action:        q.Action,
+		Amount:        q.Amount,
+		Time:          time.Now().Unix(),
+	})
+	if err != nil {
+		return stateError("cannot post to core banking system", err)
+	}
+
+	return nil
+}
+
+// MemoryCoreBanking is a reference CoreBanking keeping accounts in memory, and in a file if it was created with NewFileCoreBanking(). It's meant for tests and demos: posting a transaction debits the source account and credits the destination account if it's known
+type MemoryCoreBanking struct {
+	Balances map[string]float64   `json:"balances"`
+	History  []BankingTransaction `json:"history"`
+
+	fileName string
+	mu       sync.Mutex
+}
+
+// NewMemoryCoreBanking creates an in-memory core banking system with accounts set to the given balances
+func NewMemoryCoreBanking(balances map[string]float64) *MemoryCoreBanking {
+	b := make(map[string]float64, len(balances))
+	for a, v := range balances {
+		b[a] = v
+	}
+
+	return &MemoryCoreBanking{Balances: b, History: make([]BankingTransaction, 0)}
+}
+
+// NewFileCoreBanking creates a core banking system backed by a json file with the fields of MemoryCoreBanking. The file is created if it doesn't exist and written to after every transaction posted
+func NewFileCoreBanking(fileName string) (*MemoryCoreBanking, error) {
+	cbs := NewMemoryCoreBanking(nil)
+	cbs.fileName = fileName
+
+	buf, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return cbs, nil
+	}
+	if err != nil {
+		return nil, stateError("cannot read core banking file", err)
+	}
+	err = json.Unmarshal(buf, cbs)
+	if err != nil {
+		return nil, stateError("malformed core banking file", err)
+	}
+
+	return cbs, nil
+}
+
+// GetBalance of account
+func (cbs *MemoryCoreBanking) GetBalance(account string) (float64, error) {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	b, ok := cbs.Balances[account]
+	if !ok {
+		return 0, ErrUnknownAccount
+	}
+
+	return b, nil
+}
+
+// GetHistory of the transactions on account since a given time
+func (cbs *MemoryCoreBanking) GetHistory(account string, since time.Time) ([]BankingTransaction, error) {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	if _, ok := cbs.Balances[account]; !ok {
+		return nil, errors.New("unknown account " + account)
+	}
+
+	ret := make([]BankingTransaction, 0)
+	for _, tx := range cbs.History {
+		if (tx.SourceAccount == account || tx.DestAccount == account) && tx.Time >= since.Unix() {
+			ret = append(ret, tx)
+		}
+	}
+
+	return ret, nil
+}
+
+// PostTransaction debits the source account and credits the destination account if the bank holds it
+func (cbs *MemoryCoreBanking) PostTransaction(tx BankingTransaction) error {
+	cbs.mu.Lock()
+	defer cbs.mu.Unlock()
+
+	if _, ok := cbs.Balances[tx.SourceAccount]; !ok {
+		return errors.New("unknown account " + tx.SourceAccount)
+	}
+	cbs.Balances[tx.SourceAccount] -= tx.Amount
+	if _, ok := cbs.Balances[tx.DestAccount]; ok {
+		cbs.Balances[tx.DestAccount] += tx.Amount
+	}
+	cbs.History = append(cbs.History, tx)
+
+	if cbs.fileName == "" {
+		return nil
+	}
+	buf, err := json.MarshalIndent(cbs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(cbs.fileName, buf, 0644)
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestBindCoreBanking(t *testing.T) {
+	defer SetCoreBanking(coreBanking)
+	SetCoreBanking(NewMemoryCoreBanking(map[string]float64{testAccount: 500}))
+	rules := []ARule{{Rule: "Balance < Amount ? 'deny' : 'nil'"}}
+
+	m := map[string]interface{}{"SourceAccount": testAccount}
+	if err := bindCoreBanking(m, rules); err != nil || m["Balance"] != 500.0 {
+		t.Errorf("bindCoreBanking() = %v, Balance %v", err, m["Balance"])
+	}
+
+	// no rule uses the balance: the core banking system isn't asked
+	m = map[string]interface{}{"SourceAccount": "ZZ99"}
+	if err := bindCoreBanking(m, []ARule{{Rule: "Amount > 100 ? 'deny' : 'nil'"}}); err != nil {
+		t.Errorf("bindCoreBanking() = %v", err)
+	}
+
+	if err := bindCoreBanking(m, rules); KindOf(err) != KindNotFound {
+		t.Errorf("bindCoreBanking() on an unknown account = %v, want a not found error", err)
+	}
+}
+
+func TestMemoryCoreBankingPostTransaction(t *testing.T) {
+	cbs := NewMemoryCoreBanking(map[string]float64{testAccount: 500, "CD34": 0})
+
+	if err := cbs.PostTransaction(BankingTransaction{SourceAccount: testAccount, DestAccount: "CD34", Amount: 200, Time: at("2026-03-01T12:00:00Z").Unix()}); err != nil {
+		t.Fatal(err)
+	}
+	if cbs.Balances[testAccount] != 300 || cbs.Balances["CD34"] != 200 {
+		t.Errorf("balances %v", cbs.Balances)
+	}
+	if err := cbs.PostTransaction(BankingTransaction{SourceAccount: "ZZ99", Amount: 1}); err == nil {
+		t.Error("PostTransaction() from an unknown account succeeded")
+	}
+
+	history, err := cbs.GetHistory("CD34", at("2026-03-01T00:00:00Z"))
+	if err != nil || len(history) != 1 {
+		t.Errorf("GetHistory() = %v, %v", history, err)
+	}
+	if history, _ := cbs.GetHistory("CD34", at("2026-03-02T00:00:00Z")); len(history) != 0 {
+		t.Errorf("GetHistory() since the next day = %v", history)
+	}
+}
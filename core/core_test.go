@@ -0,0 +1,108 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	c "../common"
+	pb "../protos"
+	tpr "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	sgn "github.com/hyperledger/sawtooth-sdk-go/signing"
+)
+
+const testAccount = "AB12XF3"
+
+// useMemoryState points core at an empty MemoryState. the returned function puts the previous backend back
+func useMemoryState() (*MemoryState, func()) {
+	r, w := backendReader, backendWriter
+	s := NewMemoryState()
+	SetStateBackend(s, s)
+
+	return s, func() { SetStateBackend(r, w) }
+}
+
+// useBankKeys has the bank sign with keys generated for the test. the returned function puts the bank's key files back
+func useBankKeys(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "bank")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := sgn.NewSecp256k1Context()
+	priv := ctx.NewRandomPrivateKey()
+	prefix := filepath.Join(dir, "bank")
+	err = ioutil.WriteFile(prefix+".priv", []byte(priv.AsHex()+"\n"), 0600)
+	if err == nil {
+		err = ioutil.WriteFile(prefix+".pub", []byte(ctx.GetPublicKey(priv).AsHex()+"\n"), 0600)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	keys, pub := bankKeysFile, bankPubKeyFile
+	bankKeysFile, bankPubKeyFile = prefix, prefix+".pub"
+	bankPubKeyOnce = sync.Once{}
+
+	return func() {
+		bankKeysFile, bankPubKeyFile = keys, pub
+		bankPubKeyOnce = sync.Once{}
+		os.RemoveAll(dir)
+	}
+}
+
+// newSigner a signer with a key generated for the test, and its public key
+func newSigner() (*sgn.Signer, []byte) {
+	ctx := sgn.NewSecp256k1Context()
+	priv := ctx.NewRandomPrivateKey()
+
+	return c.GetSigner(priv), ctx.GetPublicKey(priv).AsBytes()
+}
+
+// signed wraps p, a payload of type typ on testAccount, in a signed payload signed by signer, whose public key is pubKey
+func signed(t *testing.T, typ string, p interface{}, signer *sgn.Signer, pubKey []byte) *c.SignedPayload {
+	pl, err := c.EncodePayload(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &c.SignedPayload{Version: c.PayloadVersion, SourceAccount: testAccount, Type: typ, SignerPubKey: pubKey, Signature: signer.Sign(pl), Payload: pl}
+}
+
+// submit the transactions to s in one batch. returns its status
+func submit(t *testing.T, s *MemoryState, txs ...*tpr.Transaction) *BatchStatus {
+	id, err := s.SubmitBatch(txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ := s.Status(id, false)
+
+	return status
+}
+
+// putRule writes r on initiator, a group or c.DefaultGroupName for account level rules, straight to the state as set_initiator_rule would. returns r with its hash
+func putRule(t *testing.T, s *MemoryState, initiator string, r ARule) ARule {
+	address := initiatorRule(initiatorRootStateAddress(testAccount), initiator, r.Rule)
+	r.RuleHash = getRuleHash(address)
+	data, err := encodeState(&pb.Rule{Rule: r.Rule, RuleHash: r.RuleHash, ValidFrom: r.ValidFrom, ValidUntil: r.ValidUntil, Priority: int32(r.Priority), Override: r.Override})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetState(map[string][]byte{address: data})
+
+	return r
+}
+
+// at parses the RFC 3339 times of the tests
+func at(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
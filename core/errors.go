@@ -0,0 +1,67 @@
+package core
+
+import (
+	"errors"
+)
+
+// ErrorKind classifies the errors returned by Handle(), WrapInTx() and Apply() so the callers can react: lambda maps kinds to http status codes, the transaction processor tells invalid transactions from internal errors
+type ErrorKind string
+
+// Kinds of errors
+const (
+	KindValidation    ErrorKind = "validation"    // malformed payload, bad rule, inconsistent rules, ...
+	KindAuthorization ErrorKind = "authorization" // bad signature, unknown key, missing role
+	KindNotFound      ErrorKind = "not_found"     // nothing in the state where something was expected, e.g., an unknown pending tx
+	KindState         ErrorKind = "state"         // the state, the rest api or the core banking system failed us
+	KindInternal      ErrorKind = "internal"      // everything else, i.e., bugs
+)
+
+// Error is the error returned by core. Err is the cause, if any
+type Error struct {
+	Kind ErrorKind
+	Msg  string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+
+	return e.Msg
+}
+
+// Unwrap for errors.Is() and errors.As()
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of err. errors that don't come from core are internal errors
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	return KindInternal
+}
+
+func validationError(msg string, err error) error {
+	return &Error{Kind: KindValidation, Msg: msg, Err: err}
+}
+
+func authorizationError(msg string) error {
+	return &Error{Kind: KindAuthorization, Msg: msg}
+}
+
+func notFoundError(msg string) error {
+	return &Error{Kind: KindNotFound, Msg: msg}
+}
+
+func stateError(msg string, err error) error {
+	return &Error{Kind: KindState, Msg: msg, Err: err}
+}
+
+func internalError(msg string, err error) error {
+	return &Error{Kind: KindInternal, Msg: msg, Err: err}
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+
+	c "../common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+)
+
+// entries per page of state when the query has no limit, as the rest api
+const fakePageLimit = 100
+
+// FakeRestAPI mimics the /state, /batches, /batch_statuses and /blocks endpoints of the sawtooth rest api on top of a MemoryState, so RestClient, the lambda or the client can run without a validator. Batches are applied as soon as they're submitted, with the checks of the transaction processor. Close() it when done
+type FakeRestAPI struct {
+	*httptest.Server
+	State *MemoryState
+}
+
+// NewFakeRestAPI starts a fake rest api serving state. Its URL is what RestClient and c.RestAPI point at
+func NewFakeRestAPI(state *MemoryState) *FakeRestAPI {
+	f := &FakeRestAPI{State: state}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", f.handleState)
+	mux.HandleFunc("/batches", f.handleBatches)
+	mux.HandleFunc("/batch_statuses", f.handleBatchStatuses)
+	mux.HandleFunc("/blocks", f.handleBlocks)
+	f.Server = httptest.NewServer(mux)
+
+	return f
+}
+
+// handleState as the rest api does it: 404 for a full address without data, an empty list for a partial one, pages of limit entries from address start. Note: the fake keeps no history, every head is the current state
+func (f *FakeRestAPI) handleState(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	address := q.Get("address")
+	addresses, data, _ := f.State.Read(address)
+	if len(addresses) == 0 && len(address) == AddressLength {
+		writeRestError(w, http.StatusNotFound, StateNotFound, "State Not Found", "There is no state data at the address specified")
+		return
+	}
+
+	limit := fakePageLimit
+	if l := q.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > c.RestAPIPageLimit {
+			writeRestError(w, http.StatusBadRequest, 53, "Invalid Paging Query", "Paging request failed as written. One or more of the \"min\", \"max\", or \"count\" query parameters were invalid or out of range")
+			return
+		}
+	}
+	first := 0
+	if start := q.Get("start"); start != "" {
+		first = sort.SearchStrings(addresses, start)
+	}
+	last := first + limit
+	if last > len(addresses) {
+		last = len(addresses)
+	}
+
+	entries := make([]map[string]interface{}, 0, last-first)
+	for i := first; i < last; i++ {
+		entries = append(entries, map[string]interface{}{
+			"address": addresses[i],
+			"data":    b64.StdEncoding.EncodeToString(data[i]),
+		})
+	}
+
+	head := q.Get("head")
+	if head == "" {
+		head = "fake"
+	}
+	paging := map[string]interface{}{"limit": limit}
+	if last < len(addresses) {
+		q.Set("head", head)
+		q.Set("start", addresses[last])
+		paging["next_position"] = addresses[last]
+		paging["next"] = f.URL + "/state?" + q.Encode()
+	}
+	writeRestJSON(w, http.StatusOK, map[string]interface{}{
+		"data":   entries,
+		"head":   head,
+		"link":   f.URL + r.URL.String(),
+		"paging": paging,
+	})
+}
+
+// handleBatches applies the batches of the batch list one after the other. a batch is all or nothing, an invalid one doesn't stop the next ones
+func (f *FakeRestAPI) handleBatches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeRestError(w, http.StatusMethodNotAllowed, 3, "Method Not Allowed", "Batches must be submitted with POST")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeRestError(w, http.StatusBadRequest, 34, "No Batches Submitted", err.Error())
+		return
+	}
+	var batchList batch_pb2.BatchList
+	err = proto.Unmarshal(body, &batchList)
+	if err != nil || len(batchList.Batches) == 0 {
+		writeRestError(w, http.StatusBadRequest, 35, "Protobuf Not Decodable", "The protobuf BatchList you submitted was malformed and could not be read")
+		return
+	}
+
+	ids := make([]string, len(batchList.Batches))
+	for i, b := range batchList.Batches {
+		ids[i] = b.HeaderSignature
+		// Note: the validator retries transactions failing with an internal error. the fake doesn't, the status of the batch stays UNKNOWN
+		f.State.apply(b.HeaderSignature, b.Transactions)
+	}
+
+	writeRestJSON(w, http.StatusAccepted, map[string]interface{}{
+		"link": f.URL + "/batch_statuses?id=" + strings.Join(ids, ","),
+	})
+}
+
+// handleBatchStatuses batches are never pending here, wait is ignored
+func (f *FakeRestAPI) handleBatchStatuses(w http.ResponseWriter, r *http.Request) {
+	ids := strings.Split(r.URL.Query().Get("id"), ",")
+
+	statuses := make([]BatchStatus, len(ids))
+	for i, id := range ids {
+		status, _ := f.State.Status(id, false)
+		statuses[i] = *status
+	}
+
+	writeRestJSON(w, http.StatusOK, StatusRespBody{Data: statuses, Link: f.URL + r.URL.String()})
+}
+
+// handleBlocks the head of the chain, the only block the fake knows of: its number is the number of batches committed
+func (f *FakeRestAPI) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	f.State.mu.Lock()
+	num := f.State.blockNum
+	f.State.mu.Unlock()
+
+	writeRestJSON(w, http.StatusOK, map[string]interface{}{
+		"data": []map[string]interface{}{{
+			"header":           map[string]interface{}{"block_num": strconv.FormatUint(num, 10)},
+			"header_signature": "fake-" + strconv.FormatUint(num, 10),
+		}},
+		"head": "fake-" + strconv.FormatUint(num, 10),
+		"link": f.URL + r.URL.String(),
+	})
+}
+
+func writeRestJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeRestError the error body of the rest api, see ErrorBody
+func writeRestError(w http.ResponseWriter, code, errorCode int, title, message string) {
+	writeRestJSON(w, code, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    errorCode,
+			"title":   title,
+			"message": message,
+		},
+	})
+}
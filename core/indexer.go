@@ -0,0 +1,347 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/messaging"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_event_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/events_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_receipt_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	zmq "github.com/pebbe/zmq4"
+)
+
+// Indexer is a local read model of the state of some accounts: rules, groups, pub keys, recipients, pending txs, ... It loads their state from the rest api then applies the state deltas the validator sends on every block commit. As a StateReader, see SetStateBackend(), it serves reads from memory unless it's more than MaxLag blocks behind the head of the chain, or the address isn't in the namespace of one of its accounts: the rest api serves those. When the chain forks, i.e., a block doesn't extend the last block applied or the head has another id than the block applied at its height, the index no longer serves reads and is loaded again. Note: the index is lost on restart. TODO persist it, e.g., in bbolt, with the block it's at
+type Indexer struct {
+	MaxLag  uint64        // blocks the index can be behind the head of the chain and still serve reads
+	HeadTTL time.Duration // how long the head of the chain, read from the rest api, is trusted. see Invalidate()
+
+	rest       *RestClient
+	namespaces []string
+	state      *MemoryState
+
+	mu       sync.Mutex
+	synced   bool   // the state was loaded and the subscription is live
+	forked   bool   // the chain moved off the last block applied: the state must be loaded again
+	blockNum uint64 // of the last block applied
+	blockID  string // of the last block applied, "" until a block event follows a load
+	head     uint64 // block number of the head of the chain
+	headAt   time.Time
+}
+
+// event types of the validator
+const (
+	blockCommitEvent = "sawtooth/block-commit"
+	stateDeltaEvent  = "sawtooth/state-delta"
+)
+
+// NewIndexer returns an indexer of the state of accounts, falling back on rest. Run() it to fill it
+func NewIndexer(rest *RestClient, accounts []string) *Indexer {
+	namespaces := make([]string, len(accounts))
+	for i, a := range accounts {
+		// Note: permission tags are empty as of this version so an account has one namespace, see InitiatorPermissionTag
+		namespaces[i] = Namespace(familyName(a, ""))
+	}
+
+	return &Indexer{
+		HeadTTL:    time.Second,
+		rest:       rest,
+		namespaces: namespaces,
+		state:      NewMemoryState(),
+	}
+}
+
+// Run subscribes to the state deltas of the accounts at the validator at url, loads their state and keeps it up to date. It returns when the connection fails, reads go to the rest api until it's run again
+func (i *Indexer) Run(url string) error {
+	defer i.setSynced(false)
+
+	context, err := zmq.NewContext()
+	if err != nil {
+		return stateError("cannot create zmq context", err)
+	}
+	defer context.Term()
+	conn, err := messaging.NewConnection(context, zmq.DEALER, url, false)
+	if err != nil {
+		return stateError("cannot reach validator", err)
+	}
+	defer conn.Close()
+
+	err = i.subscribe(conn)
+	if err != nil {
+		return err
+	}
+	// the events of the blocks committed while we load the state wait in the connection. they're applied on top of it: the state ends up as of the last of them
+	err = i.load()
+	if err != nil {
+		return err
+	}
+	i.setSynced(true)
+
+	for {
+		_, msg, err := conn.RecvMsg()
+		if err != nil {
+			return stateError("event subscription failed", err)
+		}
+		if msg.MessageType != validator_pb2.Message_CLIENT_EVENTS {
+			continue
+		}
+
+		var events events_pb2.EventList
+		err = proto.Unmarshal(msg.Content, &events)
+		if err != nil {
+			return stateError("malformed events", err)
+		}
+		err = i.apply(&events)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Read serves from the index if it's recent enough, see Indexer
+func (i *Indexer) Read(address string) ([]string, [][]byte, error) {
+	if !i.indexed(address) || !i.current() {
+		return i.rest.Read(address)
+	}
+
+	i.mu.Lock()
+	state := i.state
+	i.mu.Unlock()
+
+	return state.Read(address)
+}
+
+// Pin returns the index as of the last block applied if it can serve reads, see Read(), the rest api pinned to the head otherwise. apply() replaces the state of the index on every block rather than changing it, so the reads of a pin all see the same block. the addresses the index doesn't hold are read from the rest api, pinned on the first of them
+func (i *Indexer) Pin() (StateReader, error) {
+	if !i.current() {
+		return i.rest.Pin()
+	}
+
+	i.mu.Lock()
+	state := i.state
+	i.mu.Unlock()
+
+	return &indexPin{index: i, state: state}, nil
+}
+
+// indexPin is the state of an Indexer as Pin() pinned it
+type indexPin struct {
+	index *Indexer
+	state *MemoryState
+
+	once sync.Once
+	rest StateReader
+	err  error
+}
+
+func (p *indexPin) Read(address string) ([]string, [][]byte, error) {
+	if p.index.indexed(address) {
+		return p.state.Read(address)
+	}
+
+	p.once.Do(func() {
+		p.rest, p.err = p.index.rest.Pin()
+	})
+	if p.err != nil {
+		return nil, nil, p.err
+	}
+
+	return p.rest.Read(address)
+}
+
+// Invalidate tells the indexer the chain moved, e.g., we just committed a transaction: the next Read() checks the head again so that it reads what was written
+func (i *Indexer) Invalidate() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.headAt = time.Time{}
+}
+
+// BlockNum of the last block applied to the index and whether it's live
+func (i *Indexer) BlockNum() (uint64, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.blockNum, i.synced
+}
+
+func (i *Indexer) subscribe(conn *messaging.ZmqConnection) error {
+	filters := make([]*events_pb2.EventFilter, len(i.namespaces))
+	for j, ns := range i.namespaces {
+		filters[j] = &events_pb2.EventFilter{Key: "address", MatchString: "^" + ns + ".*", FilterType: events_pb2.EventFilter_REGEX_ANY}
+	}
+	req, err := proto.Marshal(&client_event_pb2.ClientEventsSubscribeRequest{
+		Subscriptions: []*events_pb2.EventSubscription{
+			{EventType: blockCommitEvent},
+			{EventType: stateDeltaEvent, Filters: filters},
+		},
+	})
+	if err != nil {
+		return internalError("cannot encode subscription", err)
+	}
+
+	corrID, err := conn.SendNewMsg(validator_pb2.Message_CLIENT_EVENTS_SUBSCRIBE_REQUEST, req)
+	if err != nil {
+		return stateError("cannot subscribe to events", err)
+	}
+	_, msg, err := conn.RecvMsgWithId(corrID)
+	if err != nil {
+		return stateError("cannot subscribe to events", err)
+	}
+
+	var resp client_event_pb2.ClientEventsSubscribeResponse
+	err = proto.Unmarshal(msg.Content, &resp)
+	if err != nil {
+		return stateError("malformed subscription response", err)
+	}
+	if resp.Status != client_event_pb2.ClientEventsSubscribeResponse_OK {
+		return stateError("subscription refused: "+resp.ResponseMessage, nil)
+	}
+
+	return nil
+}
+
+// load the state of the accounts from the rest api. it's at least as recent as the head before the load, which is where the index starts. the id of the last block applied is that of the next block event: the events that waited during the load are of older blocks, they don't tell forks
+func (i *Indexer) load() error {
+	num, _, err := i.rest.head()
+	if err != nil {
+		return err
+	}
+
+	state := NewMemoryState()
+	for _, ns := range i.namespaces {
+		addresses, data, err := i.rest.Read(ns)
+		if err != nil {
+			return err
+		}
+		for j, a := range addresses {
+			state.SetState(map[string][]byte{a: data[j]})
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.state = state
+	i.blockNum, i.blockID = num, ""
+	i.forked = false
+
+	return nil
+}
+
+// apply the events of a block: its state deltas, on a copy of the state which then replaces it along with the number and id of the block, see Pin(). a block that doesn't extend the last block applied is on another fork of the chain: the deltas of the abandoned blocks are not reverted so the state is loaded again instead
+func (i *Indexer) apply(events *events_pb2.EventList) error {
+	var num uint64
+	var id, previous string
+	committed := false
+	for _, e := range events.Events {
+		if e.EventType != blockCommitEvent {
+			continue
+		}
+		for _, a := range e.Attributes {
+			switch a.Key {
+			case "block_num":
+				var err error
+				num, err = strconv.ParseUint(a.Value, 10, 64)
+				if err != nil {
+					return stateError("malformed block number "+a.Value, err)
+				}
+				committed = true
+			case "block_id":
+				id = a.Value
+			case "previous_block_id":
+				previous = a.Value
+			}
+		}
+	}
+
+	i.mu.Lock()
+	forked := i.forked || (committed && i.blockID != "" && previous != i.blockID)
+	if forked {
+		i.forked = true
+	}
+	i.mu.Unlock()
+	if forked {
+		return i.load()
+	}
+
+	var state *MemoryState // the state of the block, if it changes ours
+	for _, e := range events.Events {
+		if e.EventType != stateDeltaEvent {
+			continue
+		}
+		var changes transaction_receipt_pb2.StateChangeList
+		err := proto.Unmarshal(e.Data, &changes)
+		if err != nil {
+			return stateError("malformed state delta", err)
+		}
+		for _, c := range changes.StateChanges {
+			if !i.indexed(c.Address) {
+				continue
+			}
+			if state == nil {
+				state = i.state.clone()
+			}
+			if c.Type == transaction_receipt_pb2.StateChange_DELETE {
+				state.DeleteState([]string{c.Address})
+			} else {
+				state.SetState(map[string][]byte{c.Address: c.Value})
+			}
+		}
+	}
+
+	i.mu.Lock()
+	if state != nil {
+		i.state = state
+	}
+	if committed {
+		i.blockNum, i.blockID = num, id
+	}
+	i.mu.Unlock()
+
+	return nil
+}
+
+func (i *Indexer) indexed(address string) bool {
+	for _, ns := range i.namespaces {
+		if strings.HasPrefix(address, ns) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// current tells whether the index is live, on the chain and at most MaxLag blocks behind the head of the chain. a head at the height of the last block applied but with another id is a fork: the index is loaded again on the next block
+func (i *Indexer) current() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.synced || i.forked {
+		return false
+	}
+	if time.Since(i.headAt) > i.HeadTTL {
+		head, id, err := i.rest.head()
+		if err != nil {
+			return false
+		}
+		if head == i.blockNum && i.blockID != "" && id != i.blockID {
+			i.forked = true
+			return false
+		}
+		i.head = head
+		i.headAt = time.Now()
+	}
+
+	return i.blockNum+i.MaxLag >= i.head
+}
+
+func (i *Indexer) setSynced(synced bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.synced = synced
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/events_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_receipt_pb2"
+)
+
+// block the events of a block committed on top of previous, changing address to value, or deleting it if value is nil
+func block(t *testing.T, num uint64, id, previous, address string, value []byte) *events_pb2.EventList {
+	events := []*events_pb2.Event{{
+		EventType: blockCommitEvent,
+		Attributes: []*events_pb2.Event_Attribute{
+			{Key: "block_num", Value: strconv.FormatUint(num, 10)},
+			{Key: "block_id", Value: id},
+			{Key: "previous_block_id", Value: previous},
+		},
+	}}
+	if address != "" {
+		change := &transaction_receipt_pb2.StateChange{Address: address, Value: value, Type: transaction_receipt_pb2.StateChange_SET}
+		if value == nil {
+			change.Type = transaction_receipt_pb2.StateChange_DELETE
+		}
+		data, err := proto.Marshal(&transaction_receipt_pb2.StateChangeList{StateChanges: []*transaction_receipt_pb2.StateChange{change}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, &events_pb2.Event{EventType: stateDeltaEvent, Data: data})
+	}
+
+	return &events_pb2.EventList{Events: events}
+}
+
+func read(t *testing.T, state StateReader, address string) string {
+	_, data, err := state.Read(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		return ""
+	}
+
+	return string(data[0])
+}
+
+func TestIndexer(t *testing.T) {
+	s := NewMemoryState()
+	_, addresses := putEntries(s, 2)
+	a, b := addresses[0], addresses[1]
+	api := NewFakeRestAPI(s)
+	defer api.Close()
+
+	i := NewIndexer(&RestClient{URL: api.URL}, []string{testAccount})
+	i.MaxLag = 10
+	if err := i.load(); err != nil {
+		t.Fatal(err)
+	}
+	i.setSynced(true)
+
+	// the state of the rest api changes behind the index: the index only sees the deltas
+	s.SetState(map[string][]byte{a: []byte("rest")})
+	if got := read(t, i, a); got == "rest" {
+		t.Errorf("Read() = %q, want the index", got)
+	}
+
+	before, err := i.Pin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := i.apply(block(t, 1, "b1", "b0", a, []byte("one"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.apply(block(t, 2, "b2", "b1", b, nil)); err != nil {
+		t.Fatal(err)
+	}
+	after, err := i.Pin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num, live := i.BlockNum(); num != 2 || !live {
+		t.Errorf("BlockNum() = %d, %v", num, live)
+	}
+
+	// a pin keeps the block it was taken at
+	if got := read(t, before, a); got == "one" {
+		t.Errorf("Read() of a pin taken before the block = %q", got)
+	}
+	if got := read(t, before, b); got == "" {
+		t.Error("Read() of a pin taken before the block doesn't see the address deleted since")
+	}
+	if got := read(t, after, a); got != "one" {
+		t.Errorf("Read() after the block = %q, want one", got)
+	}
+	if got := read(t, after, b); got != "" {
+		t.Errorf("Read() of an address deleted = %q", got)
+	}
+
+	// the addresses of other accounts come from the rest api
+	other := Namespace(familyName("ZZ99ZZ9", ""))
+	s.SetState(map[string][]byte{other + HexdigestStr("x")[:AddressLength-len(other)]: []byte("rest")})
+	if got := read(t, after, other); got != "rest" {
+		t.Errorf("Read() of another account = %q, want the rest api", got)
+	}
+
+	// a block on another fork: the index is loaded again from the rest api
+	if err := i.apply(block(t, 3, "c3", "c2", "", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, i, a); got != "rest" {
+		t.Errorf("Read() after a fork = %q, want the state of the rest api", got)
+	}
+	if got := read(t, after, a); got != "one" {
+		t.Errorf("Read() of a pin taken before the fork = %q, want one", got)
+	}
+
+	// behind the head by more than MaxLag: the rest api serves reads
+	s.blockNum = 100
+	i.Invalidate()
+	s.SetState(map[string][]byte{b: []byte("head")})
+	if got := read(t, i, b); got != "head" {
+		t.Errorf("Read() of a lagging index = %q, want the rest api", got)
+	}
+	pinned, err := i.Pin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := pinned.(*RestClient); !ok || r.Head != "fake-100" {
+		t.Errorf("Pin() of a lagging index = %v, want the rest api at the head", pinned)
+	}
+}
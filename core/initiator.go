@@ -1,11 +1,10 @@
 package core
 
 import (
-	"encoding/json"
-	"errors"
+	"time"
 
 	c "../common"
-	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	pb "../protos"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
 )
 
@@ -46,104 +45,139 @@ const (
 )
 
 // Apply applier for setting new account rules
-func (*PayloadSetInitiatorRule) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadSetInitiatorRule) Apply(pl []byte, context StateContext) error {
 	var p PayloadSetInitiatorRule
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed set initiator rule payload", err)
 	}
 
 	address := initiatorRule(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.Rule)
 	ruleHash := getRuleHash(address)
 	rule := p.Rule
 
-	newRule := NewRule(rule, ruleHash)
+	newRule, err := NewRule(rule, ruleHash)
+	if err != nil {
+		return err
+	}
+	err = checkValidity(p.ValidFrom, p.ValidUntil)
+	if err != nil {
+		return err
+	}
+	err = checkOverride(p.Override)
+	if err != nil {
+		return err
+	}
+	err = checkPriority(p.Priority)
+	if err != nil {
+		return err
+	}
+	err = checkFinalRule(p.SourceAccount, signerOf(context), address, p.Override == OverrideFinal, contextReader(context))
+	if err != nil {
+		return err
+	}
 
-	r, err := json.Marshal(newRule)
+	// Note: the address is that of the rule, whatever its validity, priority and override mode. setting the same rule again replaces them
+	r, err := encodeState(&pb.Rule{Rule: newRule.Rule, RuleHash: newRule.RuleHash, ValidFrom: p.ValidFrom, ValidUntil: p.ValidUntil, Priority: int32(p.Priority), Override: p.Override})
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	addresses, err := context.SetState(map[string][]byte{
 		address: r,
 	})
 	if err != nil || len(addresses) == 0 {
-		return errors.New("error setting new rule")
+		return stateError("error setting new rule", err)
 	}
 
 	return nil
 }
 
 // Apply applier for deleting new account rules
-func (*PayloadDeleteInitiatorRule) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadDeleteInitiatorRule) Apply(pl []byte, context StateContext) error {
 	var p PayloadDeleteInitiatorRule
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed delete initiator rule payload", err)
+	}
+	if len(p.RuleHash) != fieldLength {
+		return validationError("malformed rule hash "+p.RuleHash, nil)
 	}
 
 	address := initiatorRuleHash(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.RuleHash)
+	err = checkFinalRule(p.SourceAccount, signerOf(context), address, false, contextReader(context))
+	if err != nil {
+		return err
+	}
 
 	addresses, err := context.DeleteState([]string{address})
-	if err != nil || len(addresses) == 0 {
-		return errors.New("error deleting rule " + p.RuleHash)
+	if err != nil {
+		return stateError("error deleting rule "+p.RuleHash, err)
+	}
+	if len(addresses) == 0 {
+		return notFoundError("no rule " + p.RuleHash + " on " + p.Initiator)
 	}
 
 	return nil
 }
 
 // Apply add a transactor to a group of transactors
-func (*PayloadAddInitiatorToGroup) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadAddInitiatorToGroup) Apply(pl []byte, context StateContext) error {
 	var p PayloadAddInitiatorToGroup
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed add initiator to group payload", err)
 	}
 
 	address := initiatorGroup(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.Group)
 
-	g := []byte(p.Group)
+	g, err := encodeState(&pb.Group{Name: p.Group})
+	if err != nil {
+		return err
+	}
 
 	addresses, err := context.SetState(map[string][]byte{
 		address: g,
 	})
 	if err != nil || len(addresses) == 0 {
-		return errors.New("error adding group")
+		return stateError("error adding group", err)
 	}
 
 	return nil
 }
 
 // Apply remove a transactor from a group of transactors
-func (*PayloadRemoveInitiatorFromGroup) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadRemoveInitiatorFromGroup) Apply(pl []byte, context StateContext) error {
 	var p PayloadRemoveInitiatorFromGroup
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed remove initiator from group payload", err)
 	}
 
 	address := initiatorGroup(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.Group)
 
 	addresses, err := context.DeleteState([]string{address})
-	if err != nil || len(addresses) == 0 {
-		return errors.New("error removing group")
+	if err != nil {
+		return stateError("error removing group", err)
+	}
+	if len(addresses) == 0 {
+		return notFoundError(p.Initiator + " is not in group " + p.Group)
 	}
 
 	return nil
 }
 
 // Apply set the public keys for a given transactor, typically one for every channel
-func (*PayloadSetInitiatorPubKeys) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadSetInitiatorPubKeys) Apply(pl []byte, context StateContext) error {
 	var p PayloadSetInitiatorPubKeys
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed set initiator pub keys payload", err)
 	}
 
-	pubKeys := p.PubKeys
-	pkEnc, err := json.Marshal(pubKeys)
+	pkEnc, err := encodeState(&pb.PubKeys{Keys: p.PubKeys})
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	address := initiatorPubKeys(initiatorRootStateAddress(p.SourceAccount), p.Initiator)
@@ -153,34 +187,37 @@ func (*PayloadSetInitiatorPubKeys) Apply(pl []byte, context *processor.Context)
 
 	addresses, err := context.SetState(m)
 	if err != nil || len(addresses) == 0 {
-		return errors.New("error setting pub keys")
+		return stateError("error setting pub keys", err)
 	}
 
 	return nil
 }
 
 // Apply delete public keys for transactor
-func (*PayloadDeleteInitiatorPubKeys) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadDeleteInitiatorPubKeys) Apply(pl []byte, context StateContext) error {
 	var p PayloadDeleteInitiatorPubKeys
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed delete initiator pub keys payload", err)
 	}
 
 	pubKeysAddress := initiatorPubKeys(initiatorRootStateAddress(p.SourceAccount), p.Initiator)
 
 	m, err := context.GetState([]string{pubKeysAddress})
 	if err != nil {
-		panic(err)
+		return stateError("error reading pub keys", err)
 	}
-	var pubKeys []string
-	err = json.Unmarshal(m[pubKeysAddress], &pubKeys)
+	if len(m[pubKeysAddress]) == 0 {
+		return notFoundError("no pub keys for " + p.Initiator)
+	}
+	var pubKeys pb.PubKeys
+	err = decodeState(statePubKeys, m[pubKeysAddress], &pubKeys)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	var remainingKeys = make([]string, 0)
-	for _, storedKey := range pubKeys {
+	for _, storedKey := range pubKeys.Keys {
 		found := false
 		for _, inKey := range p.PubKeys {
 			if storedKey == inKey {
@@ -198,238 +235,374 @@ func (*PayloadDeleteInitiatorPubKeys) Apply(pl []byte, context *processor.Contex
 		addresses := []string{pubKeysAddress}
 		delAddresses, err := context.DeleteState(addresses)
 		if err != nil || len(delAddresses) != len(addresses) {
-			// TODO change return type to error code
-			return errors.New("error deleting pub key")
+			return stateError("error deleting pub key", err)
 		}
 	} else {
-		enc, err := json.Marshal(remainingKeys)
+		enc, err := encodeState(&pb.PubKeys{Keys: remainingKeys})
 		if err != nil {
-			panic(err)
+			return err
 		}
 
 		// set the state to the remaining keys
 		addresses, err := context.SetState(map[string][]byte{pubKeysAddress: enc})
 		if err != nil || len(addresses) == 0 {
-			panic(err)
+			return stateError("error setting pub keys", err)
 		}
 	}
 	return nil
 }
 
 // Handle for listing of initiator and recipient specific rules
-func (*PayloadListInitiatorRules) Handle(pl []byte) map[string]interface{} {
+func (*PayloadListInitiatorRules) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
 	var p PayloadListInitiatorRules
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed list initiator rules payload", err)
 	}
 
-	hashes, rules := extractInitiatorRules(&p)
+	accountRules, err := extractInitiatorRules(&p, state)
+	if err != nil {
+		return nil, err
+	}
+	hashes, rules := tabulateRules(accountRules)
+
+	// statuses, valid_from, valid_until, priorities and overrides go with rules, entry for entry. times are RFC 3339, empty when unbounded
+	now := time.Now()
+	statuses := make([]string, len(accountRules))
+	validFrom := make([]string, len(accountRules))
+	validUntil := make([]string, len(accountRules))
+	priorities := make([]int, len(accountRules))
+	overrides := make([]string, len(accountRules))
+	for i, r := range accountRules {
+		statuses[i] = r.Status(now)
+		validFrom[i] = formatValidity(r.ValidFrom)
+		validUntil[i] = formatValidity(r.ValidUntil)
+		priorities[i] = r.Priority
+		overrides[i] = OverrideOverridable
+		if r.Final() {
+			overrides[i] = OverrideFinal
+		}
+	}
 
 	return map[string]interface{}{
-		"rules":     rules,
-		"hashes":    hashes,
-		"initiator": p.Initiator,
-	}
+		"rules":       rules,
+		"hashes":      hashes,
+		"statuses":    statuses,
+		"valid_from":  validFrom,
+		"valid_until": validUntil,
+		"priorities":  priorities,
+		"overrides":   overrides,
+		"initiator":   p.Initiator,
+	}, nil
 }
 
 // Handle for listing of initiator and recipient specific rules
-func (*PayloadListInitiatorGroups) Handle(pl []byte) map[string]interface{} {
+func (*PayloadListInitiatorGroups) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
 	var p PayloadListInitiatorGroups
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed list initiator groups payload", err)
 	}
 
 	address := initiatorWildCardGroups(initiatorRootStateAddress(p.SourceAccount), p.Initiator)
-	_, groupsB := SubmitStateReq(address)
+	_, groupsB, err := state.Read(address)
+	if err != nil {
+		return nil, err
+	}
 
 	ret := make([]string, len(groupsB))
 	for i, group := range groupsB {
-		ret[i] = string(group)
+		ret[i], err = groupName(group)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return map[string]interface{}{
 		"groups":    ret,
 		"initiator": p.Initiator,
-	}
+	}, nil
 }
 
 // Handle for listing of initiator and recipient specific rules
-func (*PayloadListInitiatorPubKeys) Handle(pl []byte) map[string]interface{} {
+func (*PayloadListInitiatorPubKeys) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
 	var p PayloadListInitiatorPubKeys
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed list initiator pub keys payload", err)
 	}
 
 	address := initiatorPubKeys(initiatorRootStateAddress(p.SourceAccount), p.Initiator)
-	_, pubKeys := SubmitStateReq(address)
+	_, pubKeys, err := state.Read(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKeys) == 0 {
+		return nil, notFoundError("no pub keys for " + p.Initiator)
+	}
 
-	var keys []string
-	err = json.Unmarshal(pubKeys[0], &keys)
+	var keys pb.PubKeys
+	err = decodeState(statePubKeys, pubKeys[0], &keys)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return map[string]interface{}{
-		"pubKeys":   keys,
+		"pubKeys":   keys.Keys,
 		"initiator": p.Initiator,
-	}
+	}, nil
 }
 
 // WrapInTx SignedPayload with PayloadSetInitiatorRule to submit to validator
-func (*PayloadSetInitiatorRule) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadSetInitiatorRule) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for set initiator rule transaction ")
+		return nil, authorizationError("invalid signature for set initiator rule transaction")
 	}
 
 	var p PayloadSetInitiatorRule
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed set initiator rule payload", err)
 	}
 
-	outputs := []string{initiatorRule(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.Rule)}
+	address := initiatorRule(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.Rule)
+
+	// reject rules that can never fire or that make existing rules dead before they make it to the state
+	newRule, err := NewRule(p.Rule, getRuleHash(address))
+	if err != nil {
+		return nil, err
+	}
+	err = checkValidity(p.ValidFrom, p.ValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	if p.ValidUntil != 0 && p.ValidUntil <= time.Now().Unix() {
+		return nil, validationError("rule has already expired", nil)
+	}
+	err = checkOverride(p.Override)
+	if err != nil {
+		return nil, err
+	}
+	err = checkPriority(p.Priority)
+	if err != nil {
+		return nil, err
+	}
+	newRule.ValidFrom, newRule.ValidUntil, newRule.Priority, newRule.Override = p.ValidFrom, p.ValidUntil, p.Priority, p.Override
+	err = checkConsistency(p.SourceAccount, p.Initiator, newRule, state)
+	if err != nil {
+		return nil, err
+	}
+	// final rules are the bank's: only bank admins set them, or replace them by setting the same rule again
+	err = checkFinalRule(p.SourceAccount, pl.SignerPubKey, address, p.Override == OverrideFinal, leafReader(state))
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []string{address}
 	inputs := outputs
 	dependencies := []string{}
 	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of set initiator rule transaction is not authorised")
+		return nil, authorizationError("signer of set initiator rule transaction is not authorised")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
 }
 
 // WrapInTx SignedPayload with PayloadDeleteInitiatorRule to submit to validator
-func (*PayloadDeleteInitiatorRule) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadDeleteInitiatorRule) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for delete initiator rule transaction ")
+		return nil, authorizationError("invalid signature for delete initiator rule transaction")
 	}
 
 	var p PayloadDeleteInitiatorRule
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed delete initiator rule payload", err)
+	}
+
+	if len(p.RuleHash) != fieldLength {
+		return nil, validationError("malformed rule hash "+p.RuleHash, nil)
 	}
 
 	outputs := []string{initiatorRuleHash(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.RuleHash)}
 	inputs := outputs
 	dependencies := []string{}
 	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of delete initiator rule transaction is not authorised")
+		return nil, authorizationError("signer of delete initiator rule transaction is not authorised")
+	}
+	err = checkFinalRule(p.SourceAccount, pl.SignerPubKey, outputs[0], false, leafReader(state))
+	if err != nil {
+		return nil, err
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
 }
 
 // WrapInTx SignedPayload with PayloadAddInitiatorToGroup to submit to validator
-func (*PayloadAddInitiatorToGroup) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadAddInitiatorToGroup) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for add initiator to group transaction ")
+		return nil, authorizationError("invalid signature for add initiator to group transaction")
 	}
 
 	var p PayloadAddInitiatorToGroup
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed add initiator to group payload", err)
 	}
 
 	outputs := []string{initiatorGroup(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.Group)}
 	inputs := outputs
 	dependencies := []string{}
 	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of add initiator to group transaction is not authorised")
+		return nil, authorizationError("signer of add initiator to group transaction is not authorised")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
 }
 
 // WrapInTx SignedPayload with PayloadRemoveInitiatorFromGroup to submit to validator
-func (*PayloadRemoveInitiatorFromGroup) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadRemoveInitiatorFromGroup) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for remove initiator from group transaction ")
+		return nil, authorizationError("invalid signature for remove initiator from group transaction")
 	}
 
 	var p PayloadRemoveInitiatorFromGroup
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed remove initiator from group payload", err)
 	}
 
 	outputs := []string{initiatorGroup(initiatorRootStateAddress(p.SourceAccount), p.Initiator, p.Group)}
 	inputs := outputs
 	dependencies := []string{}
 	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of remove initiator from group transaction is not authorised")
+		return nil, authorizationError("signer of remove initiator from group transaction is not authorised")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
 }
 
 // WrapInTx SignedPayload with PayloadSetInitiatorPubKeys to submit to validator
-func (*PayloadSetInitiatorPubKeys) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadSetInitiatorPubKeys) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for set initiator pub keys transaction ")
+		return nil, authorizationError("invalid signature for set initiator pub keys transaction")
 	}
 
 	var p PayloadSetInitiatorPubKeys
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed set initiator pub keys payload", err)
 	}
 
 	outputs := []string{initiatorPubKeys(initiatorRootStateAddress(p.SourceAccount), p.Initiator)}
 	inputs := outputs
 	dependencies := []string{}
 	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of set initiator pub keys transaction is not authorised")
+		return nil, authorizationError("signer of set initiator pub keys transaction is not authorised")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
 }
 
 // WrapInTx SignedPayload with PayloadDeleteInitiatorPubKeys to submit to validator
-func (*PayloadDeleteInitiatorPubKeys) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadDeleteInitiatorPubKeys) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for delete initiator pub keys transaction ")
+		return nil, authorizationError("invalid signature for delete initiator pub keys transaction")
 	}
 
 	var p PayloadDeleteInitiatorPubKeys
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed delete initiator pub keys payload", err)
 	}
 
 	outputs := []string{initiatorPubKeys(initiatorRootStateAddress(p.SourceAccount), p.Initiator)}
 	inputs := outputs
 	dependencies := []string{}
 	fn := familyName(p.SourceAccount, InitiatorPermissionTag)
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of delete initiator pub keys transaction is not authorised")
+		return nil, authorizationError("signer of delete initiator pub keys transaction is not authorised")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
 }
 
+// checkFinalRule only bank admins may sign with signerPubKey when setting a final rule, when final, or when the rule at address already is final. Apply() checks it in the context of the transaction, WrapInTx() through the rest api so that the client knows straight away
+func checkFinalRule(sourceAccount string, signerPubKey []byte, address string, final bool, read stateReader) error {
+	if !final {
+		data, err := read(address)
+		if err != nil || len(data) == 0 {
+			return err
+		}
+		rs, err := unmarshalRules([][]byte{data})
+		if err != nil {
+			return err
+		}
+		final = rs[0].Final()
+	}
+	if !final {
+		return nil
+	}
+
+	ok, err := isBankAdmin(sourceAccount, InitiatorPermissionTag, signerPubKey, read)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return authorizationError("only bank admins set, change or delete final rules")
+	}
+
+	return nil
+}
+
 type initiatorRootAddressType string
 
+// groupName decodes the group at an address of initiatorWildCardGroups()
+func groupName(data []byte) (string, error) {
+	var g pb.Group
+	err := decodeState(stateGroup, data, &g)
+	if err != nil {
+		return "", err
+	}
+
+	return g.Name, nil
+}
+
 func initiatorRootStateAddress(sourceAccount string) initiatorRootAddressType {
 	return initiatorRootAddressType(Namespace(familyName(sourceAccount, InitiatorPermissionTag)) + initiatorNamespace)
 }
@@ -475,10 +648,12 @@ func initiatorPubKeys(root initiatorRootAddressType, initiator string) string {
 	return CheckLength(initiatorWildCard(root, initiator) + pubKeysSubspace + HexdigestStr(dummyString)[:fieldLength])
 }
 
-func extractInitiatorRules(pl *PayloadListInitiatorRules) ([]string, []string) {
+func extractInitiatorRules(pl *PayloadListInitiatorRules, state StateReader) ([]ARule, error) {
 	address := initiatorWildCardRules(initiatorRootStateAddress(pl.SourceAccount), pl.Initiator)
-	_, rules := SubmitStateReq(address)
+	_, rules, err := state.Read(address)
+	if err != nil {
+		return nil, err
+	}
 
-	accountRules := unmarshalRules(rules)
-	return tabulateRules(accountRules)
+	return unmarshalRules(rules)
 }
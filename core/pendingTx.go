@@ -2,14 +2,13 @@ package core
 
 import (
 	"encoding/hex"
-	"encoding/json"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
 
 	c "../common"
-	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	pb "../protos"
 	sgn "github.com/hyperledger/sawtooth-sdk-go/signing"
 )
 
@@ -30,6 +29,7 @@ type PayloadSetPendingTx struct {
 	RequiredMinSigs []int    // list of minimum required sigs: so, RequiredMinSigs[0] applies to the signers in AuthorisedSigs[0], a string with Transactor{} ID's separated by commas
 	TransactionID   string
 	Initiator       string // this is the initiator who initiated the query that resulted in this pending tx
+	QueryTime       int64  `json:",omitempty"` // unix time of the query. the time rules saw, see common.BindClock
 }
 
 // constants used in state address calculations
@@ -40,18 +40,21 @@ const (
 	initiatorSubspace   = "12"
 )
 
-// PendingTxSigsInfo convenient structure to store required sigs info in the state
+// PendingTxSigsInfo convenient structure to work with required sigs info. stored as a protobuf PendingTxSigsInfo
 type PendingTxSigsInfo struct {
-	AuthorisedSigs  []map[string]bool // one map per rule triggered. value true means signer signed
-	RequiredMinSigs []int
+	AuthorisedSigs  []map[string]bool `json:"authorised_sigs"` // one map per rule triggered. value true means signer signed
+	RequiredMinSigs []int             `json:"required_min_sigs"`
 }
 
 // Apply applier for making a transaction pending
-func (*PayloadSetPendingTx) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadSetPendingTx) Apply(pl []byte, context StateContext) error {
 	var p PayloadSetPendingTx
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed set pending tx payload", err)
+	}
+	if len(p.TransactionID) != pendingTxUIDLength {
+		return validationError("malformed transaction id "+p.TransactionID, nil)
 	}
 
 	root := pendingTxStateRootAddress(p.SourceAccount)
@@ -63,28 +66,34 @@ func (*PayloadSetPendingTx) Apply(pl []byte, context *processor.Context) error {
 	m[txAddress] = p.BankTransaction
 
 	sigsInfo := initRequiredSigners(p.AuthorisedSigs, p.RequiredMinSigs, p.Initiator)
-	sigsEnc, err := json.Marshal(*sigsInfo)
+	sigsEnc, err := encodeState(sigsInfoToProto(sigsInfo))
 	if err != nil {
-		panic(err)
+		return err
 	}
 	m[sigsAddress] = sigsEnc
 
-	m[initiatorAddress] = []byte(p.Initiator)
+	m[initiatorAddress], err = encodeState(&pb.PendingTxInitiator{Initiator: p.Initiator, QueryTime: p.QueryTime})
+	if err != nil {
+		return err
+	}
 
 	addresses, err := context.SetState(m)
 	if err != nil || len(addresses) != len(m) {
-		return errors.New("error setting pending tx")
+		return stateError("error setting pending tx", err)
 	}
 
 	return nil
 }
 
 // Apply applier for closing a pending transaction. typical scenario is cancellation of the transaction
-func (*PayloadClosePendingTx) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadClosePendingTx) Apply(pl []byte, context StateContext) error {
 	var p PayloadClosePendingTx
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed close pending tx payload", err)
+	}
+	if len(p.TransactionID) != pendingTxUIDLength {
+		return validationError("malformed transaction id "+p.TransactionID, nil)
 	}
 
 	// check if initator is the one who also initiated pending transaction that is to be cancelled
@@ -92,25 +101,32 @@ func (*PayloadClosePendingTx) Apply(pl []byte, context *processor.Context) error
 	initiatorAddress := pendingTxInitiator(pendingRootAddress, p.TransactionID)
 	m, err := context.GetState([]string{initiatorAddress})
 	if err != nil {
-		panic(err)
+		return stateError("error reading pending tx "+p.TransactionID, err)
+	}
+	if len(m[initiatorAddress]) == 0 {
+		return notFoundError("no pending transaction " + p.TransactionID)
 	}
 
-	if string(m[initiatorAddress]) != p.Initiator {
-		return &processor.InvalidTransactionError{Msg: "pending transaction can only be cancelled by party who initiated transaction"}
+	var pendingInitiator pb.PendingTxInitiator
+	err = decodeState(statePendingTxInitiator, m[initiatorAddress], &pendingInitiator)
+	if err != nil {
+		return err
+	}
+	if pendingInitiator.Initiator != p.Initiator {
+		return authorizationError("pending transaction can only be cancelled by party who initiated transaction")
 	}
 
 	// check if pubkey is known to belong to initiator
 	initiatorRootAddress := initiatorRootStateAddress(p.SourceAccount)
 	pubKeysAddress := initiatorPubKeys(initiatorRootAddress, p.Initiator)
-	m, err = context.GetState([]string{pubKeysAddress})
-	initiatorPubKeys := make([]string, 0)
-	err = json.Unmarshal(m[pubKeysAddress], &initiatorPubKeys)
+	initiatorPubKeys, err := readPubKeys(pubKeysAddress, contextReader(context))
+	if err != nil {
+		return err
+	}
 
 	goodKey := checkKey(hex.EncodeToString(p.InitiatorKey), initiatorPubKeys)
-	//////// TODO only keeping this for future tests of error prograpagation: this returns goodkey=false . goodKey := checkKey(string(p.InitiatorKey), initiatorPubKeys)
 	if !goodKey {
-		// TODO TODO TODO TODO decide what to do: panic or invalidtransactionerror?
-		return &processor.InvalidTransactionError{Msg: "pubic key in transaction to cancel pending transaction is not recognised as a key for pending transaction initiator"}
+		return authorizationError("pubic key in transaction to cancel pending transaction is not recognised as a key for pending transaction initiator")
 	}
 
 	// Note: instead of calculating the next 2 addresses, could I have just calculated the pending tx wild card and passed that to DeleteState()?
@@ -120,20 +136,29 @@ func (*PayloadClosePendingTx) Apply(pl []byte, context *processor.Context) error
 
 	delAddresses, err := context.DeleteState(addresses)
 	if err != nil || len(delAddresses) != len(addresses) {
-		// TODO change return type to error code
-		///////////////////////////////////////////////////return errors.New("error deleting pending tx")
-		panic("deladdresses")
+		return stateError("error deleting pending tx "+p.TransactionID, err)
 	}
 
 	return nil
 }
 
 // Apply applier for adding signatures to a pending transaction
-func (*PayloadAddSigTx) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadAddSigTx) Apply(pl []byte, context StateContext) error {
 	var p PayloadAddSigTx
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return validationError("malformed add sig tx payload", err)
+	}
+	if len(p.TransactionID) != pendingTxUIDLength {
+		return validationError("malformed transaction id "+p.TransactionID, nil)
+	}
+	// Time goes in the spend ledger, so only the bank sets it, see Handle(). a signer submitting add sig txs of their own could put the spend out of the windows of SpendSince()
+	ok, err := isBankAdmin(p.SourceAccount, typeToPermissionTag["add_sig_tx"], signerOf(context), contextReader(context))
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if !ok {
+		return authorizationError("add sig txs are submitted by the bank")
 	}
 
 	pendingTxRootAddress := pendingTxStateRootAddress(p.SourceAccount)
@@ -145,57 +170,76 @@ func (*PayloadAddSigTx) Apply(pl []byte, context *processor.Context) error {
 
 	m, err := context.GetState([]string{txAddress, sigsAddress, pubKeysAddress})
 	if err != nil {
-		panic(err)
+		return stateError("error reading pending tx "+p.TransactionID, err)
 	}
 
-	// TODO check tx was not closed before this initiator got to sign it
-	var sigsInfo PendingTxSigsInfo
-	err = json.Unmarshal(m[sigsAddress], &sigsInfo)
+	// the tx was closed, or all sigs are in already
+	if len(m[sigsAddress]) == 0 {
+		return notFoundError("no pending transaction " + p.TransactionID)
+	}
+	sigsInfo, err := readSigsInfo(m[sigsAddress])
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	// check initiator
 	indices := checkInitiator(p.Initiator, sigsInfo.AuthorisedSigs)
 	if indices == nil {
-		return &processor.InvalidTransactionError{Msg: p.Initiator + " not authorised to sign or has already signed transaction" + p.TransactionID}
+		return authorizationError(p.Initiator + " not authorised to sign or has already signed transaction" + p.TransactionID)
 	}
 
-	// check initiator key. Note: to avoid hard coding a structure for the public keys we avoid unmarshaling here. otherwise, code here will break if structure (which as of today 8/17/18 is []string) is changed in the pubkeys applier. When does this break?
+	// check initiator key. the pub keys are upcast to the current format whatever version wrote them
+	var pubKeys pb.PubKeys
+	if len(m[pubKeysAddress]) != 0 {
+		err = decodeState(statePubKeys, m[pubKeysAddress], &pubKeys)
+		if err != nil {
+			return err
+		}
+	}
 	strKey := hex.EncodeToString(p.PubKey)
-	foundKey := strings.Contains(string(m[pubKeysAddress]), strKey)
-	if !foundKey {
-		return &processor.InvalidTransactionError{Msg: strKey + " is not recognised as a public key for " + p.Initiator}
+	if !checkKey(strKey, pubKeys.Keys) {
+		return authorizationError(strKey + " is not recognised as a public key for " + p.Initiator)
 	}
 
 	// now we verify the signature. we expect the signature in the payload to be that of the stored, pending tx (a marshaled PayloadQueryAuth as of 9/6/18)
 	sgnContext := sgn.CreateContext(EncryptionAlgoName)
-	ok := sgnContext.Verify(p.Signature, m[txAddress], sgn.NewSecp256k1PublicKey(p.PubKey))
+	ok = sgnContext.Verify(p.Signature, m[txAddress], sgn.NewSecp256k1PublicKey(p.PubKey))
 	if !ok {
-		panic("Invalid signature for pending transaction " + p.TransactionID)
+		return authorizationError("Invalid signature for pending transaction " + p.TransactionID)
 	}
 
 	// OK so we have a legit Initiator with a legit key with a legit sig. so we map signer to true in the sigsInfo structure and decrement the number of required signatures
-	removeInitiator(p.Initiator, &sigsInfo, indices)
-	sigsEnc, err := json.Marshal(sigsInfo)
+	removeInitiator(p.Initiator, sigsInfo, indices)
+	sigsEnc, err := encodeState(sigsInfoToProto(sigsInfo))
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	addresses, err := context.SetState(map[string][]byte{sigsAddress: sigsEnc})
 	if err != nil || len(addresses) == 0 {
-		return &processor.InvalidTransactionError{Msg: "error updating signatures for transaction " + p.TransactionID}
+		return stateError("error updating signatures for transaction "+p.TransactionID, err)
 	}
 	// check if more sigs are required. Note that we update the state before possibly deleting it so we have a record of all signatures
 	moreSigs := checkRemainingSigs(sigsInfo.RequiredMinSigs)
 	if !moreSigs {
+		// the banking transaction goes through: it counts towards SpendSince()
+		var bankTx PayloadQueryAuth
+		err = c.DecodePayload(m[txAddress], &bankTx)
+		if err != nil {
+			return stateError("malformed pending tx in the state", err)
+		}
+		err = recordSpend(context, spendLedger(p.SourceAccount), bankTx.Amount, p.Time)
+		if err != nil {
+			return err
+		}
+
 		// leaves we haven't concerned ourselves with yet
 		initiatorAddress := pendingTxInitiator(pendingTxRootAddress, p.TransactionID)
 
 		addresses := []string{txAddress, sigsAddress, initiatorAddress}
 		delAddresses, err := context.DeleteState(addresses)
 		if err != nil || len(delAddresses) != len(addresses) {
-			panic(err)
+			return stateError("error deleting pending tx "+p.TransactionID, err)
 		}
 	}
 
@@ -203,22 +247,24 @@ func (*PayloadAddSigTx) Apply(pl []byte, context *processor.Context) error {
 }
 
 // Handle return all payloads of all pending transactions awaiting payload.Initiator's signature
-func (*PayloadListPendingTx) Handle(pl []byte) map[string]interface{} {
+func (*PayloadListPendingTx) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
 	var p PayloadListPendingTx
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed list pending tx payload", err)
 	}
 
 	pendingTxRootAddress := pendingTxStateRootAddress(p.SourceAccount)
 	rootSigsAddr := pendingTxSigsWildCard(pendingTxRootAddress)
-	sigsAddresses, sigsAllTx := SubmitStateReq(rootSigsAddr)
+	sigsAddresses, sigsAllTx, err := state.Read(rootSigsAddr)
+	if err != nil {
+		return nil, err
+	}
 	txIds := make([]string, 0)
 	for i, signersPerTx := range sigsAllTx {
-		var sigsInfo PendingTxSigsInfo
-		err = json.Unmarshal(signersPerTx, &sigsInfo)
+		sigsInfo, err := readSigsInfo(signersPerTx)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 
 		for _, signersPerRule := range sigsInfo.AuthorisedSigs {
@@ -230,73 +276,123 @@ func (*PayloadListPendingTx) Handle(pl []byte) map[string]interface{} {
 	}
 	ret := make(map[string]interface{}, 0)
 	if len(txIds) == 0 {
-		return ret
+		return ret, nil
 	}
 
 	rootPendingTxAddr := pendingTxTxWildCard(pendingTxRootAddress)
 	for _, uid := range txIds {
 		address := rootPendingTxAddr + uid
-		_, ptx := SubmitStateReq(address)
+		_, ptx, err := state.Read(address)
+		if err != nil {
+			return nil, err
+		}
+		if len(ptx) == 0 {
+			// closed in the meantime
+			continue
+		}
 
 		var px PayloadQueryAuth
-		err = json.Unmarshal(ptx[0], &px)
+		err = c.DecodePayload(ptx[0], &px)
 		if err != nil {
-			panic(err)
+			return nil, stateError("malformed pending tx in the state", err)
 		}
 
 		ret[uid] = px
 	}
 
-	return ret
+	return ret, nil
 }
 
 // Handle add sig tx. Note: add sig tx is a state changing request. Unlike other state changing requests however which just have to make sure the transaction was committed (through SubmitTx), add sig tx needs to know if all sigs have been obtained. Since the Apply() method invoked from the validator has to return error only, I added a Handle() method which checks to see if more sigs are still needed after this sig has been added
-func (*PayloadAddSigTx) Handle(pl []byte) map[string]interface{} {
+func (*PayloadAddSigTx) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
 	var p PayloadAddSigTx
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed add sig tx payload", err)
+	}
+	if len(p.TransactionID) != pendingTxUIDLength {
+		return nil, validationError("malformed transaction id "+p.TransactionID, nil)
+	}
+
+	// the time goes in the spend ledger if this is the last signature. we set it here rather than in Apply() so that all validators agree on it
+	p.Time = time.Now().Unix()
+	pl, err = c.EncodePayload(p)
+	if err != nil {
+		return nil, internalError("cannot encode add sig tx payload", err)
 	}
 
 	// create signedPayload (recreate really since this is called from a point, lambda handler, where pl is wrapped in SignedPayload. not worried about cost. and no easy way to solve the inelegant .inefficiency)
 	bankPubKey, signer := GetBankAuthTools()
 	signature := signer.Sign(pl)
 	signedPayload := c.SignedPayload{
-		Type:         "add_sig_tx",
-		SignerPubKey: bankPubKey.AsBytes(),
-		Signature:    signature,
-		Payload:      pl,
+		Version:       FamilyVersion,
+		SourceAccount: p.SourceAccount,
+		Type:          "add_sig_tx",
+		SignerPubKey:  bankPubKey.AsBytes(),
+		Signature:     signature,
+		Payload:       pl,
 	}
 
-	tx := (&PayloadAddSigTx{}).WrapInTx(&signedPayload)
-	_ = SubmitTx(tx)
+	// the banking transaction is deleted from the state with the last signature. we need it for the core banking system then
+	pendingAddress := pendingTxTx(pendingTxStateRootAddress(p.SourceAccount), p.TransactionID)
+	_, ptx, err := state.Read(pendingAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(ptx) == 0 {
+		return nil, notFoundError("no pending transaction " + p.TransactionID)
+	}
+
+	tx, err := (&PayloadAddSigTx{}).WrapInTx(&signedPayload, state)
+	if err != nil {
+		return nil, err
+	}
+	_, err = SubmitTx(tx)
+	if err != nil {
+		return nil, err
+	}
 
 	// SubmitTx polls until transaction has been committed. So, if we're here, the sig has been added and the state updated and we need to know if all sigs are in. we do that by checking if the address where the pending tx was stored is still valid because the Apply() method on *PayloadAddSigTx deletes the state after all sigs are in.
 
-	pendingAddress := pendingTxTx(pendingTxStateRootAddress(p.SourceAccount), p.TransactionID)
-	a, _ := SubmitStateReq(pendingAddress)
-	if a == nil || len(a) == 0 {
+	// Note: not state, which is from before the signature. SubmitStateReq() reads the state backend at its head
+	a, _, err := SubmitStateReq(pendingAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(a) == 0 {
 		// state leaf for the pending transaction was deleted
-		return map[string]interface{}{"action": "allow"}
+		var bankTx PayloadQueryAuth
+		err = c.DecodePayload(ptx[0], &bankTx)
+		if err != nil {
+			return nil, stateError("malformed pending tx in the state", err)
+		}
+		err = postToCoreBanking(&bankTx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"action": "allow"}, nil
 	}
 
 	// we're here therefore more sigs are still needed
-	return map[string]interface{}{"action": "pending"}
+	return map[string]interface{}{"action": "pending"}, nil
 }
 
 // Note PayloadSetPendingTx does NOT need a WrapInTx() method because it's never initiated by the client
 
 // WrapInTx signedPayload with payloadClosePendingTx to submit to validator
-func (*PayloadClosePendingTx) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadClosePendingTx) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for close pending transaction ")
+		return nil, authorizationError("invalid signature for close pending transaction")
 	}
 
 	var p PayloadClosePendingTx
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed close pending tx payload", err)
+	}
+	if len(p.TransactionID) != pendingTxUIDLength {
+		return nil, validationError("malformed transaction id "+p.TransactionID, nil)
 	}
 
 	pendingRootAddress := pendingTxStateRootAddress(p.SourceAccount)
@@ -312,25 +408,31 @@ func (*PayloadClosePendingTx) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Tra
 	dependencies := []string{}
 
 	fn := p.SourceAccount
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of close pending transaction is not authorised")
+		return nil, authorizationError("signer of close pending transaction is not authorised")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
 }
 
 // WrapInTx signedPayload with PayloadAddSigTx to submit to validator
-func (*PayloadAddSigTx) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadAddSigTx) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for add sig transaction ")
+		return nil, authorizationError("invalid signature for add sig transaction")
 	}
 
 	var p PayloadAddSigTx
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed add sig tx payload", err)
+	}
+	if len(p.TransactionID) != pendingTxUIDLength {
+		return nil, validationError("malformed transaction id "+p.TransactionID, nil)
 	}
 
 	pendingRootAddress := pendingTxStateRootAddress(p.SourceAccount)
@@ -340,15 +442,28 @@ func (*PayloadAddSigTx) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transacti
 
 	initiatorRootAddress := initiatorRootStateAddress(p.SourceAccount)
 	pubKeysAddress := initiatorPubKeys(initiatorRootAddress, p.Initiator)
+	// the last signature records the banking transaction in the spend ledger
+	ledgerAddress := spendLedger(p.SourceAccount)
 
-	inputs := []string{txAddress, sigsAddress, initiatorAddress, pubKeysAddress}
-	outputs := []string{txAddress, sigsAddress, initiatorAddress}
+	inputs := []string{txAddress, sigsAddress, initiatorAddress, pubKeysAddress, ledgerAddress}
+	outputs := []string{txAddress, sigsAddress, initiatorAddress, ledgerAddress}
 	dependencies := []string{}
 
 	fn := p.SourceAccount
-	ok = VerifyPermission(fn, pl.SignerPubKey)
+	ok, err = VerifyPermission(p.SourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, authorizationError("signer of add sig transaction is not authorised")
+	}
+	// the transaction processor only takes add sig txs from the bank, see Apply()
+	ok, err = isBankAdmin(p.SourceAccount, typeToPermissionTag[pl.Type], pl.SignerPubKey, leafReader(state))
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of add sig transaction is not authorised")
+		return nil, authorizationError("add sig txs are submitted by the bank")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
@@ -387,6 +502,39 @@ func checkRemainingSigs(numSigsLeft []int) bool {
 	return false // requirements from all rules have been met
 }
 
+func readSigsInfo(data []byte) (*PendingTxSigsInfo, error) {
+	var sigs pb.PendingTxSigsInfo
+	err := decodeState(statePendingTxSigs, data, &sigs)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &PendingTxSigsInfo{AuthorisedSigs: make([]map[string]bool, len(sigs.AuthorisedSigs)), RequiredMinSigs: make([]int, len(sigs.RequiredMinSigs))}
+	for i, s := range sigs.AuthorisedSigs {
+		ret.AuthorisedSigs[i] = make(map[string]bool, len(s.Signed))
+		for k, v := range s.Signed {
+			ret.AuthorisedSigs[i][k] = v
+		}
+	}
+	for i, n := range sigs.RequiredMinSigs {
+		ret.RequiredMinSigs[i] = int(n)
+	}
+
+	return ret, nil
+}
+
+func sigsInfoToProto(sigsInfo *PendingTxSigsInfo) *pb.PendingTxSigsInfo {
+	ret := &pb.PendingTxSigsInfo{}
+	for _, s := range sigsInfo.AuthorisedSigs {
+		ret.AuthorisedSigs = append(ret.AuthorisedSigs, &pb.Signers{Signed: s})
+	}
+	for _, n := range sigsInfo.RequiredMinSigs {
+		ret.RequiredMinSigs = append(ret.RequiredMinSigs, int32(n))
+	}
+
+	return ret
+}
+
 // check if key matches an entry in pubKeys
 func checkKey(key string, pubKeys []string) bool {
 	for _, k := range pubKeys {
@@ -484,6 +632,9 @@ func pendingTxInitiator(root pendingRootAddressType, uid string) string {
 	return CheckLength(pendingTxInitiatorWildCard(root) + formatPendingTxUID(uid))
 }
 
+// length of the ids of pending transactions, see formatPendingTxUID()
+const pendingTxUIDLength = actorLength + fieldLength
+
 func formatPendingTxUID(uid string) string {
-	return uid[:actorLength+fieldLength]
+	return uid[:pendingTxUIDLength]
 }
@@ -0,0 +1,384 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	c "../common"
+	pb "../protos"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// PayloadGrantRole give the holder of a public key a role on an account, e.g., rule_setter
+type PayloadGrantRole c.PayloadGrantRole
+
+// PayloadRevokeRole take a role away from the holder of a public key
+type PayloadRevokeRole c.PayloadRevokeRole
+
+// Note: roles live under the account family, i.e., without permission tag, like the spend ledger. The permission tag is part of the address instead
+const permissionNamespace = "04"
+
+// Apply applier for granting roles
+func (*PayloadGrantRole) Apply(pl []byte, context StateContext) error {
+	var p PayloadGrantRole
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return validationError("malformed grant role payload", err)
+	}
+
+	pubKey, err := hex.DecodeString(p.PubKey)
+	if err != nil || !isRole(p.Role) {
+		return validationError("cannot grant role "+p.Role+" to "+p.PubKey, err)
+	}
+
+	address := permissionAddress(p.SourceAccount, p.PermissionTag, pubKey)
+	roles, err := readRoles(address, contextReader(context))
+	if err != nil {
+		return err
+	}
+	if contains(roles, p.Role) {
+		return nil
+	}
+
+	return writeRoles(context, address, append(roles, p.Role))
+}
+
+// Apply applier for revoking roles
+func (*PayloadRevokeRole) Apply(pl []byte, context StateContext) error {
+	var p PayloadRevokeRole
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return validationError("malformed revoke role payload", err)
+	}
+
+	pubKey, err := hex.DecodeString(p.PubKey)
+	if err != nil || !isRole(p.Role) {
+		return validationError("cannot revoke role "+p.Role+" from "+p.PubKey, err)
+	}
+
+	address := permissionAddress(p.SourceAccount, p.PermissionTag, pubKey)
+	roles, err := readRoles(address, contextReader(context))
+	if err != nil {
+		return err
+	}
+	kept := make([]string, 0, len(roles))
+	for _, r := range roles {
+		if r != p.Role {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(roles) {
+		return nil
+	}
+
+	if len(kept) == 0 {
+		addresses, err := context.DeleteState([]string{address})
+		if err != nil || len(addresses) == 0 {
+			return stateError("error revoking role "+p.Role, err)
+		}
+		return nil
+	}
+
+	return writeRoles(context, address, kept)
+}
+
+// WrapInTx SignedPayload with PayloadGrantRole to submit to validator
+func (*PayloadGrantRole) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
+	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
+	if !ok {
+		return nil, authorizationError("invalid signature for grant role transaction")
+	}
+
+	var p PayloadGrantRole
+	err := c.DecodePayload(pl.Payload, &p)
+	if err != nil {
+		return nil, validationError("malformed grant role payload", err)
+	}
+
+	if !isRole(p.Role) {
+		return nil, validationError("unknown role "+p.Role, nil)
+	}
+
+	return wrapRolePlInTx(pl, p.SourceAccount, p.PermissionTag, p.PubKey, state)
+}
+
+// WrapInTx SignedPayload with PayloadRevokeRole to submit to validator
+func (*PayloadRevokeRole) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
+	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
+	if !ok {
+		return nil, authorizationError("invalid signature for revoke role transaction")
+	}
+
+	var p PayloadRevokeRole
+	err := c.DecodePayload(pl.Payload, &p)
+	if err != nil {
+		return nil, validationError("malformed revoke role payload", err)
+	}
+	if !isRole(p.Role) {
+		return nil, validationError("unknown role "+p.Role, nil)
+	}
+
+	return wrapRolePlInTx(pl, p.SourceAccount, p.PermissionTag, p.PubKey, state)
+}
+
+func wrapRolePlInTx(pl *c.SignedPayload, sourceAccount, permissionTag, pubKeyHex string, state StateReader) (*transaction_pb2.Transaction, error) {
+	pubKey, err := hex.DecodeString(pubKeyHex)
+	if err != nil {
+		return nil, validationError("malformed public key "+pubKeyHex, err)
+	}
+
+	outputs := []string{permissionAddress(sourceAccount, permissionTag, pubKey)}
+	inputs := outputs
+	dependencies := []string{}
+	fn := familyName(sourceAccount, "")
+
+	ok, err := VerifyPermission(sourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, authorizationError("signer of role transaction is not authorised")
+	}
+
+	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
+}
+
+// VerifyPermission verify that the holder of pubKey has a role permitting payloadType on sourceAccount. Roles are read from state, the state the request is served at (see PinState()), i.e., this is for WrapInTx() and Handle(). The transaction processor reads them from its context, see VerifyPermissionInContext()
+func VerifyPermission(sourceAccount, payloadType string, pubKey []byte, state StateReader) (bool, error) {
+	return verifyPermission(sourceAccount, payloadType, pubKey, leafReader(state))
+}
+
+// VerifyPermissionInContext is VerifyPermission() for Apply(). The permission address of the signer must be among the inputs of the transaction, CreateTransaction() takes care of that, see authInputs()
+func VerifyPermissionInContext(sourceAccount, payloadType string, pubKey []byte, context StateContext) (bool, error) {
+	return verifyPermission(sourceAccount, payloadType, pubKey, contextReader(context))
+}
+
+// VerifyInitiatorKey verify that a payload submitted by an initiator of the account, e.g., query_auth, is submitted with a key registered for the initiator, see payloadToInitiator. Like VerifyPermission() this reads the keys from state
+func VerifyInitiatorKey(pl *c.SignedPayload, state StateReader) (bool, error) {
+	return verifyInitiatorKey(pl, leafReader(state))
+}
+
+// VerifyInitiatorKeyInContext is VerifyInitiatorKey() for Apply()
+func VerifyInitiatorKeyInContext(pl *c.SignedPayload, context StateContext) (bool, error) {
+	return verifyInitiatorKey(pl, contextReader(context))
+}
+
+func verifyInitiatorKey(pl *c.SignedPayload, read stateReader) (bool, error) {
+	f, ok := payloadToInitiator[pl.Type]
+	if !ok {
+		// submitted by the bank, an admin or a rule setter: the role is what counts
+		return true, nil
+	}
+
+	initiator, pubKey := f(pl)
+	if initiator == "" {
+		return false, nil
+	}
+
+	pubKeys, err := readPubKeys(initiatorPubKeys(initiatorRootStateAddress(pl.SourceAccount), initiator), read)
+	if err != nil {
+		return false, err
+	}
+
+	return checkKey(hex.EncodeToString(pubKey), pubKeys), nil
+}
+
+// payloadToInitiator returns the initiator a payload is submitted by and the key it acts with. only for payloads submitted by initiators of the account
+var payloadToInitiator = map[string]func(pl *c.SignedPayload) (string, []byte){
+	"query_auth": func(pl *c.SignedPayload) (string, []byte) {
+		var p PayloadQueryAuth
+		if c.DecodePayload(pl.Payload, &p) != nil {
+			return "", nil
+		}
+		return p.Initiator, pl.SignerPubKey
+	},
+	"close_pending_tx": func(pl *c.SignedPayload) (string, []byte) {
+		var p PayloadClosePendingTx
+		if c.DecodePayload(pl.Payload, &p) != nil {
+			return "", nil
+		}
+		return p.Initiator, pl.SignerPubKey
+	},
+	// the bank submits add sig txs: the initiator acts with the key its signature is checked against
+	"add_sig_tx": func(pl *c.SignedPayload) (string, []byte) {
+		var p PayloadAddSigTx
+		if c.DecodePayload(pl.Payload, &p) != nil {
+			return "", nil
+		}
+		return p.Initiator, p.PubKey
+	},
+}
+
+// authInputs are the addresses the transaction processor reads to check pl on top of the inputs of its payload
+func authInputs(pl *c.SignedPayload) []string {
+	var ret []string
+	if _, ok := payloadToRole[pl.Type]; ok {
+		ret = append(ret, permissionAddress(pl.SourceAccount, typeToPermissionTag[pl.Type], pl.SignerPubKey))
+	}
+	if f, ok := payloadToInitiator[pl.Type]; ok {
+		if initiator, _ := f(pl); initiator != "" {
+			ret = append(ret, initiatorPubKeys(initiatorRootStateAddress(pl.SourceAccount), initiator))
+		}
+	}
+
+	return ret
+}
+
+// Note that roles are set by permission tag, i.e., by family name, NOT by payload. why? It's perfectly logical to expect someone permissioned to set a rule, e.g., to also be permissioned to delete it. payloadToRole says which role a payload needs
+func verifyPermission(sourceAccount, payloadType string, pubKey []byte, read stateReader) (bool, error) {
+	role, ok := payloadToRole[payloadType]
+	if !ok {
+		// queries, nothing to protect
+		return true, nil
+	}
+
+	// the bank is admin on every account. this is also how the first roles on an account are granted
+	bankPubKey, err := getBankPubKey()
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(pubKey, bankPubKey) {
+		return true, nil
+	}
+
+	roles, err := readRoles(permissionAddress(sourceAccount, typeToPermissionTag[payloadType], pubKey), read)
+	if err != nil {
+		return false, err
+	}
+
+	// admins can do everything
+	return contains(roles, role) || contains(roles, RoleBankAdmin), nil
+}
+
+// isBankAdmin whether pubKey is the bank's or holds the bank admin role on sourceAccount for permissionTag
+func isBankAdmin(sourceAccount, permissionTag string, pubKey []byte, read stateReader) (bool, error) {
+	bankPubKey, err := getBankPubKey()
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(pubKey, bankPubKey) {
+		return true, nil
+	}
+
+	roles, err := readRoles(permissionAddress(sourceAccount, permissionTag, pubKey), read)
+	if err != nil {
+		return false, err
+	}
+
+	return contains(roles, RoleBankAdmin), nil
+}
+
+// the bank's public key, read once, see getBankPubKey()
+var (
+	bankPubKeyOnce sync.Once
+	bankPubKey     []byte
+	bankPubKeyErr  error
+)
+
+// getBankPubKey returns c.BankPubKey, or the key in bankPubKeyFile if it's not set. it's read once so that Apply() doesn't depend on a file, and an error is returned rather than a panic: a transaction failing on it would be retried forever
+func getBankPubKey() ([]byte, error) {
+	bankPubKeyOnce.Do(func() {
+		key := c.BankPubKey
+		if key == "" {
+			buf, err := ioutil.ReadFile(bankPubKeyFile)
+			if err != nil {
+				bankPubKeyErr = internalError("cannot read the bank's public key", err)
+				return
+			}
+			key = strings.TrimSpace(string(buf))
+		}
+		bankPubKey, bankPubKeyErr = hex.DecodeString(key)
+		if bankPubKeyErr == nil && len(bankPubKey) == 0 {
+			bankPubKeyErr = errors.New("empty")
+		}
+		if bankPubKeyErr != nil {
+			bankPubKeyErr = internalError("malformed bank public key", bankPubKeyErr)
+		}
+	})
+
+	return bankPubKey, bankPubKeyErr
+}
+
+// stateReader reads the data at a (full) address, nil if there's none
+type stateReader func(address string) ([]byte, error)
+
+// leafReader reads the leaves of state, e.g., the state a request is served at
+func leafReader(state StateReader) stateReader {
+	return func(address string) ([]byte, error) {
+		_, data, err := state.Read(address)
+		if err != nil || len(data) == 0 {
+			return nil, err
+		}
+
+		return data[0], nil
+	}
+}
+
+func contextReader(context StateContext) stateReader {
+	return func(address string) ([]byte, error) {
+		m, err := context.GetState([]string{address})
+		if err != nil {
+			return nil, stateError("error reading "+address, err)
+		}
+		return m[address], nil
+	}
+}
+
+func readRoles(address string, read stateReader) ([]string, error) {
+	data, err := read(address)
+	if err != nil || len(data) == 0 {
+		return make([]string, 0), err
+	}
+
+	var roles pb.Roles
+	err = decodeState(stateRoles, data, &roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles.Roles, nil
+}
+
+func readPubKeys(address string, read stateReader) ([]string, error) {
+	data, err := read(address)
+	if err != nil || len(data) == 0 {
+		return make([]string, 0), err
+	}
+
+	var pubKeys pb.PubKeys
+	err = decodeState(statePubKeys, data, &pubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKeys.Keys, nil
+}
+
+func writeRoles(context StateContext, address string, roles []string) error {
+	r, err := encodeState(&pb.Roles{Roles: roles})
+	if err != nil {
+		return err
+	}
+
+	addresses, err := context.SetState(map[string][]byte{address: r})
+	if err != nil || len(addresses) == 0 {
+		return stateError("error setting roles", err)
+	}
+
+	return nil
+}
+
+func isRole(role string) bool {
+	return contains(allRoles, role)
+}
+
+// address of the roles held by pubKey on sourceAccount for permissionTag. one leaf per key
+func permissionAddress(sourceAccount, permissionTag string, pubKey []byte) string {
+	// NO permission tag in the namespace, see permissionNamespace
+	root := Namespace(familyName(sourceAccount, "")) + permissionNamespace + HexdigestStr(permissionTag)[:fieldLength]
+	return CheckLength(root + HexdigestB(pubKey)[:AddressLength-len(root)])
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"encoding/hex"
+	"strings"
+	"sync"
+	"testing"
+
+	c "../common"
+	tpr "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+func TestGrantRevokeRole(t *testing.T) {
+	s := NewMemoryState()
+	pubKey := "02a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc"
+	role := func(p interface{}) []byte {
+		pl, err := c.EncodePayload(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pl
+	}
+
+	if err := (&PayloadGrantRole{}).Apply(role(&c.PayloadGrantRole{SourceAccount: testAccount, PubKey: pubKey, Role: RoleSigner}), s); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []string{"", "admin", "set_initiator_rule"} {
+		if err := (&PayloadGrantRole{}).Apply(role(&c.PayloadGrantRole{SourceAccount: testAccount, PubKey: pubKey, Role: r}), s); KindOf(err) != KindValidation {
+			t.Errorf("granting role %q = %v, want a validation error", r, err)
+		}
+		if err := (&PayloadRevokeRole{}).Apply(role(&c.PayloadRevokeRole{SourceAccount: testAccount, PubKey: pubKey, Role: r}), s); KindOf(err) != KindValidation {
+			t.Errorf("revoking role %q = %v, want a validation error", r, err)
+		}
+	}
+	if err := (&PayloadRevokeRole{}).Apply(role(&c.PayloadRevokeRole{SourceAccount: testAccount, PubKey: pubKey, Role: RoleSigner}), s); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyPermissionWithoutBankKey(t *testing.T) {
+	pub := bankPubKeyFile
+	bankPubKeyFile = "/nonexistent/bank.pub"
+	bankPubKeyOnce = sync.Once{}
+	defer func() {
+		bankPubKeyFile = pub
+		bankPubKeyOnce = sync.Once{}
+	}()
+
+	// an error, not a panic
+	ok, err := VerifyPermissionInContext(testAccount, "set_initiator_rule", []byte{2, 3}, NewMemoryState())
+	if ok || KindOf(err) != KindInternal || err == nil {
+		t.Errorf("VerifyPermissionInContext() = %v, %v", ok, err)
+	}
+}
+
+// signers could backdate the spend of the banking transaction with add sig txs of their own, see PayloadAddSigTx.Time
+func TestAddSigTxSubmittedByTheBank(t *testing.T) {
+	defer useBankKeys(t)()
+	bankPubKey, err := getBankPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl, err := c.EncodePayload(&c.PayloadAddSigTx{SourceAccount: testAccount, TransactionID: strings.Repeat("0", pendingTxUIDLength), Initiator: "ID12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewMemoryState()
+	err = (&PayloadAddSigTx{}).Apply(pl, &signedContext{StateContext: s, signerPubKey: []byte{2, 3}})
+	if KindOf(err) != KindAuthorization {
+		t.Errorf("Apply() signed by a signer = %v, want an authorization error", err)
+	}
+	// there's no such pending tx
+	err = (&PayloadAddSigTx{}).Apply(pl, &signedContext{StateContext: s, signerPubKey: bankPubKey})
+	if KindOf(err) != KindNotFound {
+		t.Errorf("Apply() signed by the bank = %v, want a not found error", err)
+	}
+}
+
+// the transaction processor checks the signature and the role of the signer whatever the lambda checked: batches can be submitted to the rest api directly
+func TestApplyTransactionRoles(t *testing.T) {
+	defer useBankKeys(t)()
+	s := NewMemoryState()
+	bankPubKey, bank := GetBankAuthTools()
+	setter, setterPubKey := newSigner()
+
+	recipient := &c.PayloadSetRecipient{SourceAccount: testAccount, Recipient: "bob", DestAccount: "12345678"}
+	// straight to the ledger, not through WrapInTx(), which checks the role too
+	direct := func(pl *c.SignedPayload, fn string, outputs ...string) *tpr.Transaction {
+		tx, err := CreateTransaction(pl, fn, outputs, outputs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	setRecipient := func() *tpr.Transaction {
+		return direct(signed(t, "set_recipient", recipient, setter, setterPubKey), familyName(testAccount, RecipientPermissionTag), recipientAccount(recipientRootStateAddress(testAccount), "bob", "12345678"))
+	}
+	role := func(typ string, p interface{}) *tpr.Transaction {
+		var tx *tpr.Transaction
+		var err error
+		pl := signed(t, typ, p, bank, bankPubKey.AsBytes())
+		if typ == "grant_role" {
+			tx, err = (&PayloadGrantRole{}).WrapInTx(pl, s)
+		} else {
+			tx, err = (&PayloadRevokeRole{}).WrapInTx(pl, s)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	rejected := func(status *BatchStatus, msg string) bool {
+		return status.Status == "INVALID" && len(status.InvalidTransactions) == 1 && strings.Contains(status.InvalidTransactions[0].Message, msg)
+	}
+
+	if st := submit(t, s, setRecipient()); !rejected(st, "not authorised") {
+		t.Errorf("set_recipient without a role = %+v, want it rejected", st)
+	}
+	if _, err := (&PayloadSetRecipient{}).WrapInTx(signed(t, "set_recipient", recipient, setter, setterPubKey), s); KindOf(err) != KindAuthorization {
+		t.Errorf("WrapInTx() without a role = %v, want an authorization error", err)
+	}
+
+	grant := &c.PayloadGrantRole{SourceAccount: testAccount, PermissionTag: RecipientPermissionTag, PubKey: hex.EncodeToString(setterPubKey), Role: RoleRuleSetter}
+	if st := submit(t, s, role("grant_role", grant)); st.Status != "COMMITTED" {
+		t.Fatalf("grant_role by the bank = %+v", st)
+	}
+	if st := submit(t, s, setRecipient()); st.Status != "COMMITTED" {
+		t.Errorf("set_recipient by a rule setter = %+v", st)
+	}
+
+	// a rule setter doesn't grant roles, not even to itself
+	_, otherPubKey := newSigner()
+	selfGrant := &c.PayloadGrantRole{SourceAccount: testAccount, PermissionTag: RecipientPermissionTag, PubKey: hex.EncodeToString(otherPubKey), Role: RoleBankAdmin}
+	tx := direct(signed(t, "grant_role", selfGrant, setter, setterPubKey), familyName(testAccount, ""), permissionAddress(testAccount, RecipientPermissionTag, otherPubKey))
+	if st := submit(t, s, tx); !rejected(st, "not authorised") {
+		t.Errorf("grant_role by a rule setter = %+v, want it rejected", st)
+	}
+
+	// the signature must be that of the payload
+	forged := signed(t, "set_recipient", recipient, setter, setterPubKey)
+	forged.Signature = setter.Sign([]byte("something else"))
+	tx = direct(forged, familyName(testAccount, RecipientPermissionTag), recipientAccount(recipientRootStateAddress(testAccount), "bob", "12345678"))
+	if st := submit(t, s, tx); !rejected(st, "invalid signature") {
+		t.Errorf("set_recipient with a forged signature = %+v, want it rejected", st)
+	}
+
+	revoke := &c.PayloadRevokeRole{SourceAccount: testAccount, PermissionTag: RecipientPermissionTag, PubKey: hex.EncodeToString(setterPubKey), Role: RoleRuleSetter}
+	if st := submit(t, s, role("revoke_role", revoke)); st.Status != "COMMITTED" {
+		t.Fatalf("revoke_role by the bank = %+v", st)
+	}
+	if st := submit(t, s, setRecipient()); !rejected(st, "not authorised") {
+		t.Errorf("set_recipient once the role is revoked = %+v, want it rejected", st)
+	}
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"strings"
+	"time"
+
+	c "../common"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// PayloadPurgeExpiredRules deletes the rules at Addresses, which must all have expired at Time. Note: like PayloadRecordSpend it is only defined here because a client never initiates it: the bank does, see PurgeExpiredRules()
+type PayloadPurgeExpiredRules struct {
+	SourceAccount string
+	Addresses     []string // of the rules, whatever initiator or group they are set on
+	Time          int64    // unix time at which the rules were found expired. set by the bank so that all validators agree
+}
+
+// Apply applier for purging expired rules. rules deleted in the meantime are skipped
+func (*PayloadPurgeExpiredRules) Apply(pl []byte, context StateContext) error {
+	var p PayloadPurgeExpiredRules
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return validationError("malformed purge expired rules payload", err)
+	}
+
+	root := initiatorRootStateAddress(p.SourceAccount)
+	for _, a := range p.Addresses {
+		if !isRuleAddress(root, a) {
+			return validationError(a+" is not the address of a rule of "+p.SourceAccount, nil)
+		}
+	}
+
+	m, err := context.GetState(p.Addresses)
+	if err != nil {
+		return stateError("error reading rules", err)
+	}
+	expired := make([]string, 0, len(p.Addresses))
+	for _, a := range p.Addresses {
+		if len(m[a]) == 0 {
+			continue
+		}
+		rs, err := unmarshalRules([][]byte{m[a]})
+		if err != nil {
+			return err
+		}
+		if rs[0].Status(time.Unix(p.Time, 0)) != RuleExpired {
+			return validationError("rule "+rs[0].RuleHash+" has not expired", nil)
+		}
+		expired = append(expired, a)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	addresses, err := context.DeleteState(expired)
+	if err != nil || len(addresses) != len(expired) {
+		return stateError("error purging expired rules", err)
+	}
+
+	return nil
+}
+
+// PurgeExpiredRules deletes the rules of sourceAccount that have expired, on every initiator and group, and returns their hashes
+func PurgeExpiredRules(sourceAccount string) ([]string, error) {
+	now := time.Now()
+	root := initiatorRootStateAddress(sourceAccount)
+	addresses, data, err := SubmitStateReq(string(root))
+	if err != nil {
+		return nil, err
+	}
+
+	var expired, hashes []string
+	for i, a := range addresses {
+		if !isRuleAddress(root, a) {
+			continue
+		}
+		rs, err := unmarshalRules([][]byte{data[i]})
+		if err != nil {
+			return nil, err
+		}
+		if rs[0].Status(now) == RuleExpired {
+			expired = append(expired, a)
+			hashes = append(hashes, rs[0].RuleHash)
+		}
+	}
+	if len(expired) == 0 {
+		return []string{}, nil
+	}
+
+	tx, err := createPurgeExpiredRulesTx(sourceAccount, expired, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	_, err = SubmitTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+func createPurgeExpiredRulesTx(sourceAccount string, addresses []string, t int64) (*transaction_pb2.Transaction, error) {
+	payloadEnc, err := c.EncodePayload(PayloadPurgeExpiredRules{
+		SourceAccount: sourceAccount,
+		Addresses:     addresses,
+		Time:          t,
+	})
+	if err != nil {
+		return nil, internalError("cannot encode purge expired rules payload", err)
+	}
+
+	bankPubKey, signer := GetBankAuthTools()
+	signedPayload := c.SignedPayload{
+		Version:       FamilyVersion,
+		SourceAccount: sourceAccount,
+		Type:          "purge_expired_rules",
+		SignerPubKey:  bankPubKey.AsBytes(),
+		Signature:     signer.Sign(payloadEnc),
+		Payload:       payloadEnc,
+	}
+
+	outputs := addresses
+	inputs := outputs
+	dependencies := []string{}
+	fn := familyName(sourceAccount, InitiatorPermissionTag)
+
+	return CreateTransaction(&signedPayload, fn, inputs, outputs, dependencies)
+}
+
+// isRuleAddress whether address is that of a rule under root, as opposed to a group or pub keys, see initiatorRule()
+func isRuleAddress(root initiatorRootAddressType, address string) bool {
+	rest := strings.TrimPrefix(address, string(root))
+	return rest != address && len(address) == AddressLength && len(rest) > actorLength && rest[actorLength:actorLength+len(rulesSubspace)] == rulesSubspace
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	c "../common"
+)
+
+func TestPurgeExpiredRules(t *testing.T) {
+	s, restore := useMemoryState()
+	defer restore()
+	defer useBankKeys(t)()
+
+	now := time.Now().Unix()
+	expired := putRule(t, s, "ID12345", ARule{Rule: "Amount > 100 ? 'deny' : 'nil'", ValidUntil: now - 60})
+	expiredGroup := putRule(t, s, c.DefaultGroupName, ARule{Rule: "Amount > 200 ? 'deny' : 'nil'", ValidFrom: now - 120, ValidUntil: now - 60})
+	active := putRule(t, s, "ID12345", ARule{Rule: "Amount > 300 ? 'deny' : 'nil'", ValidUntil: now + 3600})
+	scheduled := putRule(t, s, c.DefaultGroupName, ARule{Rule: "Amount > 400 ? 'deny' : 'nil'", ValidFrom: now + 3600})
+
+	hashes, err := PurgeExpiredRules(testAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 2 || !contains(hashes, expired.RuleHash) || !contains(hashes, expiredGroup.RuleHash) {
+		t.Errorf("PurgeExpiredRules() = %v, want %s and %s", hashes, expired.RuleHash, expiredGroup.RuleHash)
+	}
+
+	for initiator, want := range map[string][]ARule{"ID12345": {active}, c.DefaultGroupName: {scheduled}} {
+		_, data, err := s.Read(initiatorWildCardRules(initiatorRootStateAddress(testAccount), initiator))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules, err := unmarshalRules(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(rules, want) {
+			t.Errorf("rules of %s after purge %v, want %v", initiator, rules, want)
+		}
+	}
+
+	// nothing left to purge
+	hashes, err = PurgeExpiredRules(testAccount)
+	if err != nil || len(hashes) != 0 {
+		t.Errorf("PurgeExpiredRules() = %v, %v, want nothing", hashes, err)
+	}
+}
+
+// the transaction processor checks the rules have expired at the time of the purge
+func TestPurgeRejectsRulesInForce(t *testing.T) {
+	s := NewMemoryState()
+	now := time.Now().Unix()
+	r := putRule(t, s, "ID12345", ARule{Rule: "Amount > 100 ? 'deny' : 'nil'", ValidUntil: now})
+	address := initiatorRuleHash(initiatorRootStateAddress(testAccount), "ID12345", r.RuleHash)
+
+	tests := []struct {
+		name      string
+		addresses []string
+		time      int64
+		invalid   bool
+	}{
+		{"in force", []string{address}, now - 1, true},
+		{"not a rule", []string{accountPolicy(testAccount)}, now, true},
+		{"expired", []string{address}, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl, err := c.EncodePayload(PayloadPurgeExpiredRules{SourceAccount: testAccount, Addresses: tt.addresses, Time: tt.time})
+			if err != nil {
+				t.Fatal(err)
+			}
+			context := &memoryContext{data: s.data, inputs: tt.addresses, outputs: tt.addresses}
+			err = (&PayloadPurgeExpiredRules{}).Apply(pl, context)
+			if (err != nil) != tt.invalid || (err != nil && KindOf(err) != KindValidation) {
+				t.Errorf("Apply() = %v, invalid %v", err, tt.invalid)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package core
 import (
 	"encoding/hex"
 	"encoding/json"
+	"sort"
+	"strings"
+	"time"
 
 	c "../common"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
@@ -11,93 +14,377 @@ import (
 // PayloadQueryAuth for querying the system about allowing a banking transaction to go through
 type PayloadQueryAuth c.PayloadQueryAuth
 
+// PayloadSimulateAuth for asking the system what it would do with a banking transaction, possibly under extra rules
+type PayloadSimulateAuth c.PayloadSimulateAuth
+
 // Handle to handle authorisation queries payloads
-func (*PayloadQueryAuth) Handle(pl []byte) map[string]interface{} {
+func (*PayloadQueryAuth) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
 	var p PayloadQueryAuth
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed query auth payload", err)
 	}
 
-	m := c.Struct2Map(&p)
+	// the time of the query is the time of the banking transaction for rules, the spend ledger and the pending tx, whenever they are written
+	now := time.Now()
+	m := c.BindRuleVariables(&p)
+	bindClock(m, now)
+
+	ret, err := queryRules(p.SourceAccount, p.Initiator, m, state)
+	if err != nil {
+		return nil, err
+	}
+	if ret["action"] == "deny" {
+		return ret, nil
+	}
 
-	ret := queryRules(p.SourceAccount, p.Initiator, m)
-	if ret["action"] == "allow" || ret["action"] == "deny" {
-		return ret
+	if ret["action"] == "allow" {
+		// allowed transactions count towards SpendSince()
+		if p.Amount > 0 {
+			tx, err := createRecordSpendTx(p.SourceAccount, p.Amount, now.Unix())
+			if err != nil {
+				return nil, err
+			}
+			_, err = SubmitTx(tx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
 	}
 
 	// we got here, therefore ret["action"] == "pending"
-	ptx := createSetPendingTx(p.SourceAccount, p.Initiator, pl, ret)
+	ptx, err := createSetPendingTx(p.SourceAccount, p.Initiator, pl, ret, now.Unix())
+	if err != nil {
+		return nil, err
+	}
 
 	// SubmitTx() returns linkToStatus after it has polled until the batch was processed. so, if we're here, we're really done and we have more important things to return, so we ignore linkToStatus
-	_ = SubmitTx(ptx)
+	_, err = SubmitTx(ptx)
+	if err != nil {
+		return nil, err
+	}
 
-	return ret
+	return ret, nil
 }
 
-func queryRules(sourceAccount, initiator string, m map[string]interface{}) map[string]interface{} {
-	// root address of initiator rules, groups, etc.
-	initiatorRootAddress := initiatorRootStateAddress(sourceAccount)
-	// individual rules
-	rulesAddress := initiatorWildCardRules(initiatorRootAddress, initiator)
-	_, rules := SubmitStateReq(rulesAddress)
+// Handle to handle simulated authorisation queries: the decision query_auth would make, with the trace of every rule evaluated, but nothing is written to the state
+func (*PayloadSimulateAuth) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
+	var p PayloadSimulateAuth
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return nil, validationError("malformed simulate auth payload", err)
+	}
 
-	// group rules
-	groupsAddress := initiatorWildCardGroups(initiatorRootAddress, initiator)
-	_, groups := SubmitStateReq(groupsAddress)
-	// now add the default group. account level rules are assigned to this group
-	groups = append(groups, []byte(c.DefaultGroupName))
-	gRules := make([][]byte, 0)
-	for _, group := range groups {
-		g := string(group)
-		// Note a group has its rules stored in the state under the same address structure as an individual 'initiator'. essentially a rule for a group=group_name is a rule for initiator=group_name
-		groupRulesAddress := initiatorWildCardRules(initiatorRootAddress, g)
-		_, r := SubmitStateReq(groupRulesAddress)
-		gRules = append(gRules, r...)
+	q := PayloadQueryAuth{
+		SourceAccount: p.SourceAccount,
+		Initiator:     p.Initiator,
+		Recipient:     p.Recipient,
+		Action:        p.Action,
+		Amount:        p.Amount,
+		DestAccount:   p.DestAccount,
+	}
+	m := c.BindRuleVariables(&q)
+
+	irs, grs, groupOf, err := initiatorAndGroupRules(p.SourceAccount, p.Initiator, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// hypothetical rules join the rules in force as if they had been set
+	irs, grs, hypothetical, skipped, err := addRules(p.SourceAccount, p.Initiator, p.ExtraRules, irs, grs, groupOf, state)
+	if err != nil {
+		return nil, err
+	}
+	if len(skipped) != 0 {
+		return nil, validationError("hypothetical rule set on "+skipped[0].Initiator+" does not apply to initiator "+p.Initiator, nil)
 	}
 
-	// TODO allow unless a rule rejects it. other possibility is: deny unless rule allows it. make it settable??
-	grs := unmarshalRules(gRules)
-	irs := unmarshalRules(rules)
-	accountRules := SortConflicts(map[string][]ARule{
+	policy, err := readAccountPolicy(p.SourceAccount, state)
+	if err != nil {
+		return nil, err
+	}
+	ret, trace, err := evaluateRules(irs, grs, m, policy, state)
+	if err != nil {
+		return nil, err
+	}
+	for i := range trace {
+		t := &trace[i]
+		t.Hypothetical = hypothetical[t.RuleHash]
+		switch {
+		case t.spec && p.Initiator == c.DefaultGroupName, !t.spec && groupOf[t.RuleHash] == c.DefaultGroupName:
+			t.Source = "account"
+		case t.spec:
+			t.Source = "individual"
+		default:
+			t.Source = "group"
+			t.Group = groupOf[t.RuleHash]
+		}
+	}
+
+	// the trace goes back as json: responses are flattened to strings
+	tEnc, err := json.Marshal(trace)
+	if err != nil {
+		return nil, internalError("cannot encode trace", err)
+	}
+	ret["trace"] = string(tEnc)
+
+	return ret, nil
+}
+
+// ruleTrace is the evaluation of one rule in a simulated authorisation query
+type ruleTrace struct {
+	RuleHash     string      `json:"rulehash"`
+	Rule         string      `json:"rule"`   // as set, i.e., before conflict resolution
+	Source       string      `json:"source"` // individual, group or account
+	Group        string      `json:"group,omitempty"`
+	Priority     int         `json:"priority,omitempty"`
+	Final        bool        `json:"final,omitempty"`
+	Hypothetical bool        `json:"hypothetical,omitempty"`
+	Overridden   bool        `json:"overridden,omitempty"` // the rule would have fired but a specific rule overrode it
+	Outranked    bool        `json:"outranked,omitempty"`  // the rule fired but rules of a higher priority decided
+	Result       interface{} `json:"result"`               // "nil", "deny", "allow" or the output of NofM()
+
+	spec bool // the rule came in as a specific rule
+}
+
+func queryRules(sourceAccount, initiator string, m map[string]interface{}, state StateReader) (map[string]interface{}, error) {
+	irs, grs, _, err := initiatorAndGroupRules(sourceAccount, initiator, state)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := readAccountPolicy(sourceAccount, state)
+	if err != nil {
+		return nil, err
+	}
+	ret, _, err := evaluateRules(irs, grs, m, policy, state)
+
+	return ret, err
+}
+
+// evaluateRules decides on the banking transaction described by m given the initiator rules irs, the group rules grs and the default policy of the account. rules are evaluated by decreasing priority: the rules of the priority of the first rule that fires decide, along with final rules, whatever their priority. among them 'deny' wins over NofM(), which wins over 'allow'. when no rule fires the policy decides. decided_by is the hash of the first rule of the outcome. the reason in the decision says which: "rule:" followed by the hashes of the deciding rules or "policy:" followed by the policy. only the rules in force at the time of the banking transaction are evaluated. the trace lists them in the order they were
+func evaluateRules(irs, grs []ARule, m map[string]interface{}, policy string, state StateReader) (map[string]interface{}, []ruleTrace, error) {
+	// the time comes from the query when it was bound to it, otherwise it's now
+	bindClock(m, time.Now())
+
+	// rules scheduled or expired at the time of the banking transaction are not in force
+	now := clockOf(m)
+	irs, grs = activeRules(irs, now), activeRules(grs, now)
+
+	// Balance comes from the core banking system, not from the query, and only if a rule in force needs it
+	err := bindCoreBanking(m, append(append([]ARule{}, irs...), grs...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountRules, overridden := SortConflicts(map[string][]ARule{
 		"gen":  grs,
 		"spec": irs,
 	})
 
+	// SortConflicts() guards overridden rules. the trace shows the rules as set
+	asSet := make(map[string]ARule, len(grs))
+	for _, r := range grs {
+		asSet[r.RuleHash] = r
+	}
+
+	// SortConflicts() returns the generic rules first. the sort keeps that order within a priority
+	order := make([]int, len(accountRules))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return accountRules[order[a]].Priority > accountRules[order[b]].Priority
+	})
+
+	trace := make([]ruleTrace, 0, len(accountRules))
 	var violatedRules []string     // list of violated rules prepended with their hashes
 	var authorisedSigners []string // list of lists of authorised signers. each entry is a comma-separated list
 	var minNumberOfSigners []int
-	for _, r := range accountRules {
-		ev := r.Evaluate(m)
-		if ev != "nil" { // rule evaluates to nil when no action required
-			// rule was triggered
-			if ev == "deny" {
-				violatedRules = append(violatedRules, r.RuleHash+":"+r.Rule)
-				// note we don't return after the first "deny". we want to report all the rules that trigger "deny"
-			} else if len(violatedRules) == 0 {
-				// we get here if a signoff is required for instance. but if a rule has triggered "deny" there's no point. that's why we check for length of violatedRules array
-				arInt := ev.([]interface{})
-				// govaluate doesn't see int's, only float64's. hence the acrobatics here
-				minNumberOfSigners = append(minNumberOfSigners, int(arInt[0].(float64)))
-				authorisedSigners = append(authorisedSigners, arInt[1].(string))
+	var signoffRules, allowingRules []string // hashes of the rules that returned NofM() and 'allow'
+	level, fired := 0, false                 // the priority of the first rule that fired
+	for _, i := range order {
+		r := accountRules[i]
+		ev, err := r.Evaluate(m, state)
+		if err != nil {
+			return nil, nil, err
+		}
 
+		t := ruleTrace{RuleHash: r.RuleHash, Rule: r.Rule, Result: ev, Priority: r.Priority, Final: r.Final(), spec: i >= len(grs)}
+		if g, ok := asSet[r.RuleHash]; ok && !t.spec {
+			t.Rule = g.Rule
+			if g.Rule != r.Rule && ev == "nil" {
+				unguarded, err := g.Evaluate(m, state)
+				if err != nil {
+					return nil, nil, err
+				}
+				t.Overridden = unguarded != "nil"
 			}
 		}
+		if ev != "nil" {
+			if !fired {
+				level, fired = r.Priority, true
+			}
+			t.Outranked = r.Priority < level && !r.Final()
+		}
+		trace = append(trace, t)
+
+		if ev == "nil" || t.Outranked { // rule evaluates to nil when no action required
+			continue
+		}
+		// rule was triggered
+		if ev == "deny" {
+			violatedRules = append(violatedRules, r.RuleHash+":"+r.Rule)
+			// note we don't return after the first "deny". we want to report all the rules that trigger "deny"
+		} else if ev == "allow" {
+			allowingRules = append(allowingRules, r.RuleHash)
+		} else if len(violatedRules) == 0 {
+			// we get here if a signoff is required for instance. but if a rule has triggered "deny" there's no point. that's why we check for length of violatedRules array
+			arInt, ok := ev.([]interface{})
+			if !ok || len(arInt) != 2 {
+				return nil, nil, validationError("rule "+r.RuleHash+" returns neither 'nil', 'deny', 'allow' nor NofM()", nil)
+			}
+			// govaluate doesn't see int's, only float64's. hence the acrobatics here
+			n, ok := arInt[0].(float64)
+			signers, ok2 := arInt[1].(string)
+			if !ok || !ok2 {
+				return nil, nil, validationError("rule "+r.RuleHash+" calls NofM() with the wrong arguments", nil)
+			}
+			minNumberOfSigners = append(minNumberOfSigners, int(n))
+			authorisedSigners = append(authorisedSigners, signers)
+			signoffRules = append(signoffRules, r.RuleHash)
+		}
 	}
 
+	// overridden_rules lists the hashes of the group and account level rules that were (partly) overridden by initiator rules
 	if len(violatedRules) != 0 {
-		return map[string]interface{}{"action": "deny", "violated_rules": violatedRules}
+		hashes := make([]string, len(violatedRules))
+		for i, v := range violatedRules {
+			hashes[i] = strings.SplitN(v, ":", 2)[0]
+		}
+		return map[string]interface{}{"action": "deny", "violated_rules": violatedRules, "overridden_rules": overridden, "reason": ruleReason(hashes), "decided_by": hashes[0]}, trace, nil
 	}
 
 	if len(authorisedSigners) != 0 {
-		return map[string]interface{}{"action": "pending", "authorised_sigs": authorisedSigners, "min_required_sigs": minNumberOfSigners}
+		return map[string]interface{}{"action": "pending", "authorised_sigs": authorisedSigners, "min_required_sigs": minNumberOfSigners, "overridden_rules": overridden, "reason": ruleReason(signoffRules), "decided_by": signoffRules[0]}, trace, nil
+	}
+
+	if len(allowingRules) != 0 {
+		return map[string]interface{}{"action": "allow", "overridden_rules": overridden, "reason": ruleReason(allowingRules), "decided_by": allowingRules[0]}, trace, nil
+	}
+
+	// no rule fired
+	switch policy {
+	case PolicyAllowUnlessDenied:
+		return map[string]interface{}{"action": "allow", "overridden_rules": overridden, "reason": "policy:" + policy}, trace, nil
+	case PolicyDenyUnlessAllowed:
+		return map[string]interface{}{"action": "deny", "violated_rules": []string{}, "overridden_rules": overridden, "reason": "policy:" + policy}, trace, nil
+	}
+
+	return nil, nil, stateError("unknown account policy "+policy, nil)
+}
+
+// ruleReason the reason of a decision taken by the rules with these hashes
+func ruleReason(hashes []string) string {
+	return "rule:" + strings.Join(hashes, ",")
+}
+
+// initiatorAndGroupRules returns the rules set on initiator itself and those it inherits from its groups, account level rules included. groupOf maps the hashes of the group rules to the group they are set on
+func initiatorAndGroupRules(sourceAccount, initiator string, state StateReader) (irs []ARule, grs []ARule, groupOf map[string]string, err error) {
+	// root address of initiator rules, groups, etc.
+	initiatorRootAddress := initiatorRootStateAddress(sourceAccount)
+	// individual rules
+	rulesAddress := initiatorWildCardRules(initiatorRootAddress, initiator)
+	_, rules, err := state.Read(rulesAddress)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	irs, err = unmarshalRules(rules)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// group rules
+	groups, err := initiatorGroups(sourceAccount, initiator, state)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	groupOf = make(map[string]string)
+	for _, g := range groups {
+		// Note a group has its rules stored in the state under the same address structure as an individual 'initiator'. essentially a rule for a group=group_name is a rule for initiator=group_name
+		groupRulesAddress := initiatorWildCardRules(initiatorRootAddress, g)
+		_, r, err := state.Read(groupRulesAddress)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		rs, err := unmarshalRules(r)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, rule := range rs {
+			grs = append(grs, rule)
+			groupOf[rule.RuleHash] = g
+		}
+	}
+
+	return irs, grs, groupOf, nil
+}
+
+// addRules adds rules that are not in the state to the rules of initiator: to its own rules irs, or to its group rules grs, recording the group in groupOf. Rules set on other initiators, or on groups initiator doesn't belong to, don't apply to initiator and are returned in skipped
+func addRules(sourceAccount, initiator string, rules []c.HypotheticalRule, irs, grs []ARule, groupOf map[string]string, state StateReader) (_, _ []ARule, added map[string]bool, skipped []c.HypotheticalRule, err error) {
+	groups, err := initiatorGroups(sourceAccount, initiator, state)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	root := initiatorRootStateAddress(sourceAccount)
+	added = make(map[string]bool)
+	for _, h := range rules {
+		r, err := NewRule(h.Rule, getRuleHash(initiatorRule(root, h.Initiator, h.Rule)))
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		switch {
+		case h.Initiator == initiator:
+			irs = append(irs, r)
+		case contains(groups, h.Initiator):
+			grs = append(grs, r)
+			groupOf[r.RuleHash] = h.Initiator
+		default:
+			skipped = append(skipped, h)
+			continue
+		}
+		added[r.RuleHash] = true
+	}
+
+	return irs, grs, added, skipped, nil
+}
+
+// initiatorGroups returns the groups initiator belongs to, the default group included
+func initiatorGroups(sourceAccount, initiator string, state StateReader) ([]string, error) {
+	groupsAddress := initiatorWildCardGroups(initiatorRootStateAddress(sourceAccount), initiator)
+	_, groups, err := state.Read(groupsAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, 0, len(groups)+1)
+	for _, group := range groups {
+		g, err := groupName(group)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, g)
+	}
+	// now add the default group. account level rules are assigned to this group. Note: account level rules are themselves set on initiator=DefaultGroupName and they should not also count as group rules then
+	if initiator != c.DefaultGroupName {
+		ret = append(ret, c.DefaultGroupName)
 	}
 
-	return map[string]interface{}{"action": "allow"}
+	return ret, nil
 }
 
 // Note: this lives here and not in payloadPending.go because the keys of the sigs argument which are only known here
-func createSetPendingTx(sourceAccount, initiator string, pl []byte, sigs map[string]interface{}) *transaction_pb2.Transaction {
+func createSetPendingTx(sourceAccount, initiator string, pl []byte, sigs map[string]interface{}, queryTime int64) (*transaction_pb2.Transaction, error) {
 	// first create PayloadSetPendingTx and set unique id to signature of the query auth payload
 	bankPubKey, signer := GetBankAuthTools()
 	uid := hex.EncodeToString(signer.Sign(pl)[:]) // this is just used here as a unique identifier
@@ -108,19 +395,22 @@ func createSetPendingTx(sourceAccount, initiator string, pl []byte, sigs map[str
 		RequiredMinSigs: sigs["min_required_sigs"].([]int),
 		TransactionID:   formatPendingTxUID(uid),
 		Initiator:       initiator,
+		QueryTime:       queryTime,
 	}
 
 	// now create SignedPayload to wrap in transaction
-	payloadEnc, err := json.Marshal(pendingTxPayload)
+	payloadEnc, err := c.EncodePayload(pendingTxPayload)
 	if err != nil {
-		panic(err)
+		return nil, internalError("cannot encode pending tx", err)
 	}
 	signature := signer.Sign(payloadEnc)
 	signedPayload := c.SignedPayload{
-		Type:         "set_pending_tx",
-		SignerPubKey: bankPubKey.AsBytes(),
-		Signature:    signature,
-		Payload:      payloadEnc,
+		Version:       FamilyVersion,
+		SourceAccount: sourceAccount,
+		Type:          "set_pending_tx",
+		SignerPubKey:  bankPubKey.AsBytes(),
+		Signature:     signature,
+		Payload:       payloadEnc,
 	}
 
 	pendingRoot := pendingTxStateRootAddress(sourceAccount)
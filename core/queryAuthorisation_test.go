@@ -0,0 +1,81 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateRules(t *testing.T) {
+	now := at("2026-03-01T12:00:00Z").Unix()
+	deny := ARule{Rule: "Amount > 100 ? 'deny' : 'nil'", RuleHash: "deny"}
+	allow := ARule{Rule: "Amount > 100 ? 'allow' : 'nil'", RuleHash: "allow"}
+	nofm := ARule{Rule: "Amount > 100 ? NofM(1, 'ID12345') : 'nil'", RuleHash: "nofm"}
+	small := ARule{Rule: "Amount < 10 ? 'deny' : 'nil'", RuleHash: "small"}
+	with := func(r ARule, f func(*ARule)) ARule { f(&r); return r }
+
+	tests := []struct {
+		name       string
+		irs, grs   []ARule
+		policy     string
+		action     string
+		reason     string
+		overridden []string
+	}{
+		{"no rule, allow unless denied", nil, nil, PolicyAllowUnlessDenied, "allow", "policy:" + PolicyAllowUnlessDenied, nil},
+		{"no rule, deny unless allowed", nil, nil, PolicyDenyUnlessAllowed, "deny", "policy:" + PolicyDenyUnlessAllowed, nil},
+		{"no rule fires", nil, []ARule{small}, PolicyDenyUnlessAllowed, "deny", "policy:" + PolicyDenyUnlessAllowed, nil},
+		{"allow", nil, []ARule{allow}, PolicyDenyUnlessAllowed, "allow", "rule:allow", nil},
+		{"deny wins over NofM and allow", nil, []ARule{allow, nofm, deny}, PolicyAllowUnlessDenied, "deny", "rule:deny", nil},
+		{"NofM wins over allow", nil, []ARule{allow, nofm}, PolicyAllowUnlessDenied, "pending", "rule:nofm", nil},
+		{"higher priority decides", nil, []ARule{deny, with(allow, func(r *ARule) { r.Priority = 1 })}, PolicyAllowUnlessDenied, "allow", "rule:allow", nil},
+		{"lower priority ignored", nil, []ARule{with(deny, func(r *ARule) { r.Priority = -1 }), allow}, PolicyDenyUnlessAllowed, "allow", "rule:allow", nil},
+		{"final rules always decide", nil, []ARule{with(deny, func(r *ARule) { r.Override = OverrideFinal }), with(allow, func(r *ARule) { r.Priority = 1 })}, PolicyAllowUnlessDenied, "deny", "rule:deny", nil},
+		{"priority of the first rule that fires", nil, []ARule{deny, with(small, func(r *ARule) { r.Priority = 1 })}, PolicyAllowUnlessDenied, "deny", "rule:deny", nil},
+		{"scheduled rule not in force", nil, []ARule{with(deny, func(r *ARule) { r.ValidFrom = now + 1 })}, PolicyAllowUnlessDenied, "allow", "policy:" + PolicyAllowUnlessDenied, nil},
+		{"expired rule not in force", nil, []ARule{with(deny, func(r *ARule) { r.ValidUntil = now })}, PolicyAllowUnlessDenied, "allow", "policy:" + PolicyAllowUnlessDenied, nil},
+		{"rule in force", nil, []ARule{with(deny, func(r *ARule) { r.ValidFrom, r.ValidUntil = now, now+1 })}, PolicyAllowUnlessDenied, "deny", "rule:deny", nil},
+		{"specific overrides generic", []ARule{allow}, []ARule{deny}, PolicyAllowUnlessDenied, "allow", "rule:allow", []string{"deny"}},
+		{"specific doesn't override final", []ARule{allow}, []ARule{with(deny, func(r *ARule) { r.Override = OverrideFinal })}, PolicyAllowUnlessDenied, "deny", "rule:deny", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := map[string]interface{}{"Amount": 150.0, "Recipient": "bob"}
+			bindClock(m, at("2026-03-01T12:00:00Z"))
+
+			res, _, err := evaluateRules(tt.irs, tt.grs, m, tt.policy, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res["action"] != tt.action || res["reason"] != tt.reason {
+				t.Errorf("evaluateRules() = %s (%s), want %s (%s)", res["action"], res["reason"], tt.action, tt.reason)
+			}
+			got := res["overridden_rules"].([]string)
+			if (len(got) != 0 || len(tt.overridden) != 0) && !reflect.DeepEqual(got, tt.overridden) {
+				t.Errorf("evaluateRules() overrode %v, want %v", got, tt.overridden)
+			}
+		})
+	}
+}
+
+func TestEvaluateRulesTrace(t *testing.T) {
+	grs := []ARule{
+		{Rule: "Amount > 100 ? 'deny' : 'nil'", RuleHash: "low"},
+		{Rule: "Amount > 100 ? 'allow' : 'nil'", RuleHash: "high", Priority: 1},
+		{Rule: "Amount > 100 ? 'allow' : 'nil'", RuleHash: "expired", ValidUntil: at("2026-03-01T00:00:00Z").Unix()},
+	}
+	m := map[string]interface{}{"Amount": 150.0}
+	bindClock(m, at("2026-03-01T12:00:00Z"))
+
+	_, trace, err := evaluateRules(nil, grs, m, PolicyAllowUnlessDenied, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// by decreasing priority, without the rules not in force
+	if len(trace) != 2 || trace[0].RuleHash != "high" || trace[1].RuleHash != "low" {
+		t.Fatalf("trace %v", trace)
+	}
+	if trace[0].Outranked || !trace[1].Outranked {
+		t.Errorf("outranked %v %v, want false true", trace[0].Outranked, trace[1].Outranked)
+	}
+}
@@ -1,11 +1,8 @@
 package core
 
 import (
-	"encoding/json"
-	"errors"
-
 	c "../common"
-	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	pb "../protos"
 	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
 )
 
@@ -25,112 +22,127 @@ const (
 )
 
 // Apply tie recipient to specific accounts
-func (*PayloadSetRecipient) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadSetRecipient) Apply(pl []byte, context StateContext) error {
 	var p PayloadSetRecipient
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed set recipient payload", err)
 	}
 
 	address := recipientAccount(recipientRootStateAddress(p.SourceAccount), p.Recipient, p.DestAccount)
 
-	a := []byte(p.DestAccount)
+	a, err := encodeState(&pb.RecipientAccount{DestAccount: p.DestAccount})
+	if err != nil {
+		return err
+	}
 
 	addresses, err := context.SetState(map[string][]byte{
 		address: a,
 	})
 	if err != nil || len(addresses) == 0 {
-		// TODO change return type to error code
-		return errors.New("error setting recipient")
+		return stateError("error setting recipient", err)
 	}
 
 	return nil
 }
 
 // Apply remove account details for recipient
-func (*PayloadRemoveRecipient) Apply(pl []byte, context *processor.Context) error {
+func (*PayloadRemoveRecipient) Apply(pl []byte, context StateContext) error {
 	var p PayloadRemoveRecipient
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return validationError("malformed remove recipient payload", err)
 	}
 
 	address := recipientAccount(recipientRootStateAddress(p.SourceAccount), p.Recipient, p.DestAccount)
 
 	addresses, err := context.DeleteState([]string{address})
-	if err != nil || len(addresses) == 0 {
-		// TODO change return type to error code
-		return errors.New("error setting recipient")
+	if err != nil {
+		return stateError("error removing recipient", err)
+	}
+	if len(addresses) == 0 {
+		return notFoundError("account " + p.DestAccount + " is not tied to recipient " + p.Recipient)
 	}
 
 	return nil
 }
 
 // Handle list all state information about this recipient
-func (*PayloadListRecipient) Handle(pl []byte) map[string]interface{} {
+func (*PayloadListRecipient) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
 	var p PayloadListRecipient
-	err := json.Unmarshal(pl, &p)
+	err := c.DecodePayload(pl, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed list recipient payload", err)
 	}
 
 	// TODO this assumes only info we have about recipients is accounts. when we have other information we will change the wild card address to recipientWildCard()
 	address := recipientWildCardAccounts(recipientRootStateAddress(p.SourceAccount), p.Recipient)
-	_, rules := SubmitStateReq(address)
+	_, rules, err := state.Read(address)
+	if err != nil {
+		return nil, err
+	}
 
 	rl := make([]string, len(rules))
 	for i, r := range rules {
-		rl[i] = p.Recipient + ":" + string(r)
+		var a pb.RecipientAccount
+		err = decodeState(stateRecipientAccount, r, &a)
+		if err != nil {
+			return nil, err
+		}
+		rl[i] = p.Recipient + ":" + a.DestAccount
 	}
 
 	return map[string]interface{}{
 		"accounts": rl,
-	}
+	}, nil
 
 }
 
 // WrapInTx wrap SignedPayload with PayloadSetRecipient payload in a sawtooth transaction
-func (*PayloadSetRecipient) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadSetRecipient) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for set recipient transaction ")
+		return nil, authorizationError("invalid signature for set recipient transaction")
 	}
 
 	var p PayloadSetRecipient
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed set recipient payload", err)
 	}
 
-	return wrapRecipientPlInTx(pl, p.SourceAccount, p.Recipient, p.DestAccount, pl.SignerPubKey)
+	return wrapRecipientPlInTx(pl, p.SourceAccount, p.Recipient, p.DestAccount, state)
 }
 
 // WrapInTx wrap SignedPayload with PayloadRemoveRecipient payload in a sawtooth transaction
-func (*PayloadRemoveRecipient) WrapInTx(pl *c.SignedPayload) *transaction_pb2.Transaction {
+func (*PayloadRemoveRecipient) WrapInTx(pl *c.SignedPayload, state StateReader) (*transaction_pb2.Transaction, error) {
 	ok := VerifySignature(pl.Payload, pl.Signature, pl.SignerPubKey)
 	if !ok {
-		panic("Invalid signature for set recipient transaction ")
+		return nil, authorizationError("invalid signature for remove recipient transaction")
 	}
 
 	var p PayloadRemoveRecipient
-	err := json.Unmarshal(pl.Payload, &p)
+	err := c.DecodePayload(pl.Payload, &p)
 	if err != nil {
-		panic(err)
+		return nil, validationError("malformed remove recipient payload", err)
 	}
 
-	return wrapRecipientPlInTx(pl, p.SourceAccount, p.Recipient, p.DestAccount, pl.SignerPubKey)
+	return wrapRecipientPlInTx(pl, p.SourceAccount, p.Recipient, p.DestAccount, state)
 }
 
-func wrapRecipientPlInTx(pl *c.SignedPayload, sourceAccount, recipient, destAccount string, pubKey []byte) *transaction_pb2.Transaction {
+func wrapRecipientPlInTx(pl *c.SignedPayload, sourceAccount, recipient, destAccount string, state StateReader) (*transaction_pb2.Transaction, error) {
 	root := recipientRootStateAddress(sourceAccount)
 	outputs := []string{recipientAccount(root, recipient, destAccount)}
 	inputs := outputs
 	dependencies := []string{}
 	fn := familyName(sourceAccount, RecipientPermissionTag)
 
-	ok := VerifyPermission(fn, pubKey)
+	ok, err := VerifyPermission(sourceAccount, pl.Type, pl.SignerPubKey, state)
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		panic("signer of recipient transaction setting is not authorised")
+		return nil, authorizationError("signer of recipient transaction setting is not authorised")
 	}
 
 	return CreateTransaction(pl, fn, inputs, outputs, dependencies)
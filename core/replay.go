@@ -0,0 +1,101 @@
+package core
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	c "../common"
+)
+
+// ReplayTransaction is a past banking transaction: the query_auth payload and the time it was queried at, which is the time its rules see, see common.BindClock
+type ReplayTransaction struct {
+	c.PayloadQueryAuth
+	Time int64 `json:"time"` // unix time
+}
+
+// ReplayChange is a banking transaction on which the proposed rules change the decision
+type ReplayChange struct {
+	Transaction int                    `json:"transaction"` // position in the replayed transactions, starting at 1
+	Query       ReplayTransaction      `json:"query"`
+	Change      string                 `json:"change"` // e.g. allow->deny, or "signers" when the transaction is pending either way but signed off by different signers
+	Before      map[string]interface{} `json:"before"`
+	After       map[string]interface{} `json:"after"`
+}
+
+// ReplayReport is the outcome of replaying banking transactions under proposed rules
+type ReplayReport struct {
+	Replayed int            `json:"replayed"`
+	Changed  map[string]int `json:"changed"` // number of transactions for every kind of change
+	Changes  []ReplayChange `json:"changes"`
+}
+
+// Replay runs queries through the rules in force in state, then through the rules in force plus the proposed rules and minus the rules with their hash in removed, and reports the transactions on which the decision differs. Each query is evaluated at its own time: the clock variables, the calendar and spend functions and the validity of rules are those of the time. Nothing is written to the state. Pass a snapshot (see LoadStateSnapshot()) to replay past transactions against the rules of the time, or PinState() for the rules in force now
+func Replay(state StateReader, queries []ReplayTransaction, proposed []c.HypotheticalRule, removed []string) (ReplayReport, error) {
+	report := ReplayReport{Replayed: len(queries), Changed: make(map[string]int), Changes: make([]ReplayChange, 0)}
+	for i, q := range queries {
+		if q.Time <= 0 {
+			return report, validationError("transaction "+strconv.Itoa(i+1)+" has no time", nil)
+		}
+		p := PayloadQueryAuth(q.PayloadQueryAuth)
+		m := c.BindRuleVariables(&p)
+		bindClock(m, time.Unix(q.Time, 0))
+
+		irs, grs, groupOf, err := initiatorAndGroupRules(q.SourceAccount, q.Initiator, state)
+		if err != nil {
+			return report, err
+		}
+		policy, err := readAccountPolicy(q.SourceAccount, state)
+		if err != nil {
+			return report, err
+		}
+		before, _, err := evaluateRules(irs, grs, m, policy, state)
+		if err != nil {
+			return report, err
+		}
+
+		// proposed rules set on other initiators don't apply to this transaction
+		irs, grs, _, _, err = addRules(q.SourceAccount, q.Initiator, proposed, without(irs, removed), without(grs, removed), groupOf, state)
+		if err != nil {
+			return report, err
+		}
+		after, _, err := evaluateRules(irs, grs, m, policy, state)
+		if err != nil {
+			return report, err
+		}
+
+		change := decisionChange(before, after)
+		if change == "" {
+			continue
+		}
+		report.Changed[change]++
+		report.Changes = append(report.Changes, ReplayChange{Transaction: i + 1, Query: q, Change: change, Before: before, After: after})
+	}
+
+	return report, nil
+}
+
+// returns "" when the decisions are the same
+func decisionChange(before, after map[string]interface{}) string {
+	if before["action"] != after["action"] {
+		return before["action"].(string) + "->" + after["action"].(string)
+	}
+
+	if before["action"] == "pending" && (!reflect.DeepEqual(before["authorised_sigs"], after["authorised_sigs"]) || !reflect.DeepEqual(before["min_required_sigs"], after["min_required_sigs"])) {
+		return "signers"
+	}
+
+	return ""
+}
+
+// rules without those with their hash in hashes
+func without(rules []ARule, hashes []string) []ARule {
+	ret := make([]ARule, 0, len(rules))
+	for _, r := range rules {
+		if !contains(hashes, r.RuleHash) {
+			ret = append(ret, r)
+		}
+	}
+
+	return ret
+}
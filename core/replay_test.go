@@ -0,0 +1,88 @@
+package core
+
+import (
+	"testing"
+
+	c "../common"
+)
+
+func TestReplay(t *testing.T) {
+	s, restore := useMemoryState()
+	defer restore()
+
+	// in force until noon
+	r := putRule(t, s, c.DefaultGroupName, ARule{Rule: "Amount > 1000 ? 'deny' : 'nil'", ValidUntil: at("2026-03-01T12:00:00Z").Unix()})
+	query := func(amount float64, time string) ReplayTransaction {
+		return ReplayTransaction{
+			PayloadQueryAuth: c.PayloadQueryAuth{SourceAccount: testAccount, Initiator: "ID12345", Amount: amount},
+			Time:             at(time).Unix(),
+		}
+	}
+	queries := []ReplayTransaction{
+		query(2000, "2026-03-01T11:00:00Z"), // denied before and after
+		query(2000, "2026-03-01T13:00:00Z"), // the rule had expired
+		query(50, "2026-03-01T22:30:00Z"),   // denied by the proposed rule
+		query(50, "2026-03-01T21:00:00Z"),
+	}
+	proposed := []c.HypotheticalRule{{Initiator: c.DefaultGroupName, Rule: "Hour >= 22 ? 'deny' : 'nil'"}}
+
+	report, err := Replay(s, queries, proposed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Replayed != 4 || len(report.Changes) != 1 || report.Changed["allow->deny"] != 1 {
+		t.Fatalf("Replay() = %+v", report)
+	}
+	if ch := report.Changes[0]; ch.Transaction != 3 || ch.Change != "allow->deny" || ch.Query != queries[2] {
+		t.Errorf("Replay() changed %+v", ch)
+	}
+
+	// without the rule in the state
+	report, err = Replay(s, queries, nil, []string{r.RuleHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != 1 || report.Changes[0].Transaction != 1 || report.Changes[0].Change != "deny->allow" {
+		t.Errorf("Replay() = %+v", report)
+	}
+
+	// proposed rules set on other initiators don't apply
+	report, err = Replay(s, queries, []c.HypotheticalRule{{Initiator: "CD34YG4", Rule: "Amount > 10 ? 'deny' : 'nil'"}}, nil)
+	if err != nil || len(report.Changes) != 0 {
+		t.Errorf("Replay() = %+v, %v", report, err)
+	}
+
+	_, err = Replay(s, []ReplayTransaction{{PayloadQueryAuth: queries[0].PayloadQueryAuth}}, nil, nil)
+	if KindOf(err) != KindValidation {
+		t.Errorf("Replay() of a transaction without time = %v", err)
+	}
+}
+
+// the spend of a replayed transaction is what the ledger held at its time, not what it holds now
+func TestReplaySpendSince(t *testing.T) {
+	s, restore := useMemoryState()
+	defer restore()
+
+	for _, e := range []struct {
+		amount float64
+		time   string
+	}{{800, "2026-03-01T10:00:00Z"}, {900, "2026-03-01T14:00:00Z"}} {
+		if err := recordSpend(s, spendLedger(testAccount), e.amount, at(e.time).Unix()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	queries := []ReplayTransaction{
+		{PayloadQueryAuth: c.PayloadQueryAuth{SourceAccount: testAccount, Initiator: "ID12345", Amount: 100}, Time: at("2026-03-01T12:00:00Z").Unix()},
+		{PayloadQueryAuth: c.PayloadQueryAuth{SourceAccount: testAccount, Initiator: "ID12345", Amount: 100}, Time: at("2026-03-01T15:00:00Z").Unix()},
+	}
+	proposed := []c.HypotheticalRule{{Initiator: c.DefaultGroupName, Rule: "SpendSince('day') + Amount > 1000 ? 'deny' : 'nil'"}}
+
+	report, err := Replay(s, queries, proposed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 800 + 100 at noon, 1700 + 100 at 3pm
+	if len(report.Changes) != 1 || report.Changes[0].Transaction != 2 || report.Changes[0].Change != "allow->deny" {
+		t.Errorf("Replay() = %+v", report)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	b64 "encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	c "../common"
 	"github.com/golang/protobuf/proto"
@@ -16,10 +19,9 @@ import (
 // Various codes go here
 const (
 	AddressNotFound int = 404
+	StateNotFound   int = 75 // error code of the rest api when there's no data at a full address. it returns 404 for an unknown head too
 )
 
-// TODO TODO TODO Note: make it part of the TESTING suite to check that the keys in the payloads constructed in the client are part of VarSet
-
 // StateRespBody struct for state query response body
 type StateRespBody struct {
 	Data   []map[string]interface{} `json:"Data"`
@@ -31,10 +33,10 @@ type StateRespBody struct {
 
 // PagingBody if response broken into pages
 type PagingBody struct {
-	Start        string `json:"Start"`        // address of first block on page
-	Limit        int    `json:"Limit"`        // what we retrieved
-	NextPosision string `json:"NextPosition"` // address of block at top of next page
-	Next         string `json:"Next"`         // link to next
+	Start        string `json:"Start"`         // address of first block on page
+	Limit        int    `json:"Limit"`         // what we retrieved
+	NextPosition string `json:"next_position"` // address of block at top of next page
+	Next         string `json:"Next"`          // link to next
 }
 
 // ErrorBody structure for errors
@@ -46,152 +48,300 @@ type ErrorBody struct {
 
 // StatusRespBody for status response body
 type StatusRespBody struct {
-	Data []map[string]interface{} `json:"data"`
-	Link string                   `json:"link"`
+	Data []BatchStatus `json:"data"`
+	Link string        `json:"link"`
+}
+
+// BatchStatus status of one batch. invalid transactions come with the message of the transaction processor that rejected them
+type BatchStatus struct {
+	ID                  string               `json:"id"`
+	Status              string               `json:"status"`
+	InvalidTransactions []InvalidTransaction `json:"invalid_transactions"`
+}
+
+// InvalidTransaction transaction rejected by a transaction processor
+type InvalidTransaction struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
 }
 
-// ParseStateResponse parse http response
-func parseStateResponse(resp *http.Response) ([]string, [][]byte) {
+// ParseStateResponse parse http response. the error body, if any, is left to the caller
+func parseStateResponse(resp *http.Response) (*StateRespBody, error) {
 	defer resp.Body.Close()
 
 	buf, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		panic(err)
+		return nil, stateError("cannot read state response", err)
 	}
 
 	body := &StateRespBody{}
 	err = json.Unmarshal(buf, body)
 	if err != nil {
-		panic(err)
+		return nil, stateError("malformed state response", err)
+	}
+
+	return body, nil
+}
+
+// parseStateBody the entries of a decoded state query response, unless it's an error. the data comes back base64 encoded
+func parseStateBody(body *StateRespBody) ([]string, [][]byte, error) {
+	if body.Error.Code != 0 {
+		return nil, nil, stateError("state query failed: "+body.Error.Title, errors.New(body.Error.Message))
 	}
 
+	return stateEntries(body)
+}
+
+// stateEntries the addresses and decoded data of one page of state
+func stateEntries(body *StateRespBody) ([]string, [][]byte, error) {
 	num := len(body.Data)
 	addresses := make([]string, num)
 	rules := make([][]byte, num)
 	for i, r := range body.Data {
 		address, ok := r["address"].(string)
 		if !ok {
-			panic("rest.go address type assertion failed")
+			return nil, nil, stateError("state response without address", nil)
 		}
 		addresses[i] = address
 		rulenc, ok := r["data"].(string)
 		if !ok {
-			panic("rest.go rule type assertion failed")
+			return nil, nil, stateError("state response without data at "+address, nil)
 		}
+		var err error
 		rules[i], err = b64.StdEncoding.DecodeString(rulenc)
 		if err != nil {
-			panic(err)
+			return nil, nil, stateError("malformed data at "+address, err)
 		}
 	}
 
-	return addresses, rules
+	return addresses, rules, nil
 }
 
 // ParseBatchesResponse parse response from a batch list submission to the rest api
-func parseBatchesResponse(resp *http.Response) string {
+func parseBatchesResponse(resp *http.Response) (string, error) {
 	defer resp.Body.Close()
 
 	buf, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		panic(err)
+		return "", stateError("cannot read batches response", err)
 	}
 
-	var linkToStatusDict map[string]interface{}
+	var linkToStatusDict struct {
+		Link  string    `json:"link"`
+		Error ErrorBody `json:"error"`
+	}
 	err = json.Unmarshal(buf, &linkToStatusDict)
 	if err != nil {
-		// there's an error. in this case linkToStatusDict has one key, "error", and the value is a map with keys "code", "message" and "title". the "message" was not very informative so I didn't bother printing it here
-		panic(err)
+		return "", stateError("malformed batches response", err)
+	}
+	// there's an error. in this case the response has one key, "error", and the value is a map with keys "code", "message" and "title". no errors: the response is then a map with one key, "link", with a string value
+	if linkToStatusDict.Link == "" {
+		return "", stateError("batch submission failed: "+linkToStatusDict.Error.Title, errors.New(linkToStatusDict.Error.Message))
 	}
-	// no errors: linkToStatusDict is then a map with one key, "link", with a string value
-	linkToStatus := linkToStatusDict["link"].(string)
 
-	return linkToStatus
+	return linkToStatusDict.Link, nil
 }
 
-// ParseBatchStatusesResponse returns status of batch submissions
-func parseBatchStatusesResponse(resp *http.Response) string {
+// ParseBatchStatusesResponse returns the status of the first batch in the response. invalid batches come with the messages of the transaction processor
+func parseBatchStatusesResponse(resp *http.Response) (*BatchStatus, error) {
 	defer resp.Body.Close()
 
 	buf, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		panic(err)
+		return nil, stateError("cannot read batch status response", err)
 	}
 
 	var statusResp StatusRespBody
 	err = json.Unmarshal(buf, &statusResp)
-	if err != nil {
-		panic(err)
+	if err != nil || len(statusResp.Data) == 0 {
+		return nil, stateError("malformed batch status response", err)
+	}
+
+	return &statusResp.Data[0], nil
+}
+
+// RestClient is the state backend of a sawtooth validator: it reads the state and submits batches through the rest api at URL, e.g., c.RestAPI. the default, see SetStateBackend()
+type RestClient struct {
+	URL       string
+	PageLimit int    // entries per page when reading the state, the default of the rest api if 0. Read() follows the pages whatever the limit
+	Head      string // id of the block the state is read at. if empty, each Read() is pinned to the head of its first page, see Pin()
+}
+
+// Pin returns a copy of r that reads the state at the head of the chain, r itself if it reads at a block already
+func (r *RestClient) Pin() (StateReader, error) {
+	if r.Head != "" {
+		return r, nil
 	}
 
-	status := statusResp.Data[0]["status"].(string)
+	_, id, err := r.head()
+	if err != nil {
+		return nil, err
+	}
+	pinned := *r
+	pinned.Head = id
 
-	return status
+	return &pinned, nil
 }
 
 // SubmitBatchesReq to rest api
-func submitBatchesReq(body []byte) string {
-	resp, err := http.Post(c.RestAPIBatches, "application/octet-stream", bytes.NewBuffer(body))
+func (r *RestClient) submitBatchesReq(body []byte) (string, error) {
+	resp, err := http.Post(r.URL+"/batches", "application/octet-stream", bytes.NewBuffer(body))
 	if err != nil {
-		panic(err)
+		return "", stateError("cannot reach rest api", err)
 	}
 	fmt.Printf("status code after submit batches %d\n", resp.StatusCode)
-	linkToStatus := parseBatchesResponse(resp)
 
-	return linkToStatus
+	return parseBatchesResponse(resp)
 }
 
-// SubmitStateReq to rest api
-func SubmitStateReq(address string) ([]string, [][]byte) {
-	resp, err := http.Get(c.RestAPIState + "?address=" + address)
+// SubmitStateReq reads the state from the state backend, at its head. Note: requests read all their state at one head, see PinState()
+func SubmitStateReq(address string) ([]string, [][]byte, error) {
+	return backendReader.Read(address)
+}
+
+// Read the state from the rest api, all the pages of it. the pages are read at the same head so that the entries are consistent even if blocks are committed in between
+func (r *RestClient) Read(address string) ([]string, [][]byte, error) {
+	addresses := make([]string, 0)
+	data := make([][]byte, 0)
+	head := r.Head
+	start := ""
+	for {
+		body, err := r.readPage(address, head, start)
+		if err != nil || body == nil {
+			return nil, nil, err
+		}
+
+		a, d, err := stateEntries(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		addresses = append(addresses, a...)
+		data = append(data, d...)
+
+		if head == "" {
+			head = body.Head
+		}
+		if body.Paging.Next == "" || body.Paging.NextPosition == "" {
+			break
+		}
+		start = body.Paging.NextPosition
+	}
+	if len(addresses) == 0 {
+		return nil, nil, nil
+	}
+
+	return addresses, data, nil
+}
+
+// readPage one page of state starting at address start, nil if there's no data at address, which is then a full address
+func (r *RestClient) readPage(address, head, start string) (*StateRespBody, error) {
+	q := url.Values{}
+	q.Set("address", address)
+	if head != "" {
+		q.Set("head", head)
+	}
+	if start != "" {
+		q.Set("start", start)
+	}
+	if r.PageLimit > 0 {
+		q.Set("limit", strconv.Itoa(r.PageLimit))
+	}
+
+	resp, err := http.Get(r.URL + "/state?" + q.Encode())
 	if err != nil {
-		panic(err)
+		return nil, stateError("cannot reach rest api", err)
 	}
 
-	if resp.StatusCode == AddressNotFound {
+	body, err := parseStateResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == AddressNotFound && body.Error.Code == StateNotFound {
 		return nil, nil
 	}
+	if body.Error.Code != 0 {
+		return nil, stateError("state query failed: "+body.Error.Title, errors.New(body.Error.Message))
+	}
 
-	return parseStateResponse(resp)
+	return body, nil
 }
 
-// PollStatus until the submitted batches are no longer pending
-func pollStatus(linkToStatus string) string {
-	var status string
-	for {
-		resp, err := http.Get(linkToStatus + "&wait=" + c.RestAPIWait)
+// maxPolls of the status of a batch, i.e., an hour with the default c.RestAPIWait
+const maxPolls = 12
+
+// Status of the batch with id, once it's no longer pending if wait is set. the rest api holds each poll up to c.RestAPIWait seconds, we give up after maxPolls and return the pending status
+func (r *RestClient) Status(id string, wait bool) (*BatchStatus, error) {
+	link := r.URL + "/batch_statuses?id=" + id
+	if wait {
+		link += "&wait=" + c.RestAPIWait
+	}
+
+	for i := 0; ; i++ {
+		resp, err := http.Get(link)
 		if err != nil {
-			panic(err)
+			return nil, stateError("cannot reach rest api", err)
 		}
 		fmt.Printf("status code after poll %d\n", resp.StatusCode)
-		status = parseBatchStatusesResponse(resp)
-		if status != "PENDING" {
-			break
+		status, err := parseBatchStatusesResponse(resp)
+		if err != nil || !wait || status.Status != "PENDING" || i == maxPolls-1 {
+			return status, err
 		}
 	}
-
-	return status
 }
 
-// SubmitTx for submitting transaction through restful API
-func SubmitTx(tx *tpr.Transaction) map[string]interface{} {
+// SubmitBatch the transactions in one batch through the rest api. the id of the batch is the signature of its header
+func (r *RestClient) SubmitBatch(txs []*tpr.Transaction) (string, error) {
 
 	// Note: transactor is already being approved/rejected based on Identity Transaction Family data. (logic in validator/server). as part of on-boarding bank (listed in immutable configuration file validator.toml as sole transactor on identity family) will issue transactions to list those at the company that are authorised to transact for specific transaction families, i.e., set/delete rules
 
 	// TODO handle case where keys file doesn't exist
-	batchList := createBatchList(tx)
+	batchList, err := createBatchList(txs...)
+	if err != nil {
+		return "", err
+	}
 
 	bEnc, err := proto.Marshal(batchList)
 	if err != nil {
-		panic(err)
+		return "", internalError("cannot encode batch list", err)
+	}
+
+	_, err = r.submitBatchesReq(bEnc)
+	if err != nil {
+		return "", err
+	}
+
+	return batchList.Batches[0].HeaderSignature, nil
+}
+
+// head of the chain, the latest block committed: its number and its id
+func (r *RestClient) head() (uint64, string, error) {
+	resp, err := http.Get(r.URL + "/blocks?limit=1")
+	if err != nil {
+		return 0, "", stateError("cannot reach rest api", err)
 	}
+	defer resp.Body.Close()
 
-	linkToStatus := submitBatchesReq(bEnc)
-	status := pollStatus(linkToStatus)
-	if status != "COMMITTED" {
-		panic("lambdahandler.go: polling error; status=" + status)
+	var body struct {
+		Data []struct {
+			Header struct {
+				BlockNum json.Number `json:"block_num"` // a string, uint64 are strings in the json of protobuf messages
+			} `json:"header"`
+			HeaderSignature string `json:"header_signature"` // the id of the block
+		} `json:"data"`
+		Error ErrorBody `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return 0, "", stateError("malformed blocks response", err)
+	}
+	if len(body.Data) == 0 {
+		return 0, "", stateError("blocks query failed: "+body.Error.Title, errors.New(body.Error.Message))
 	}
 
-	return map[string]interface{}{
-		"link_to_status": []string{linkToStatus},
+	num, err := strconv.ParseUint(body.Data[0].Header.BlockNum.String(), 10, 64)
+	if err != nil {
+		return 0, "", stateError("malformed block number", err)
 	}
+
+	return num, body.Data[0].HeaderSignature, nil
 }
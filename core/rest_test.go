@@ -0,0 +1,93 @@
+package core
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// putEntries writes n entries under the namespace of testAccount, straight to the state. returns their addresses, sorted
+func putEntries(s *MemoryState, n int) (string, []string) {
+	ns := Namespace(familyName(testAccount, ""))
+	for i := 0; i < n; i++ {
+		s.SetState(map[string][]byte{ns + HexdigestStr(strconv.Itoa(i))[:AddressLength-len(ns)]: []byte(strconv.Itoa(i))})
+	}
+	addresses, _, _ := s.Read(ns)
+
+	return ns, addresses
+}
+
+func TestRestClientRead(t *testing.T) {
+	s := NewMemoryState()
+	ns, want := putEntries(s, 25)
+	api := NewFakeRestAPI(s)
+	defer api.Close()
+
+	for _, limit := range []int{0, 1, 7, 25, 100} {
+		r := &RestClient{URL: api.URL, PageLimit: limit}
+		addresses, data, err := r.Read(ns)
+		if err != nil {
+			t.Fatalf("Read() with %d entries per page: %v", limit, err)
+		}
+		if !reflect.DeepEqual(addresses, want) || len(data) != len(want) {
+			t.Errorf("Read() with %d entries per page = %d entries, want %d in order", limit, len(addresses), len(want))
+		}
+	}
+
+	// no data is not an error, at a full address or under a prefix
+	r := &RestClient{URL: api.URL}
+	a, d, err := r.Read(want[0][:len(want[0])-1] + "x")
+	if a != nil || d != nil || err != nil {
+		t.Errorf("Read() of a full address without data = %v, %v, %v", a, d, err)
+	}
+	a, d, err = r.Read(Namespace(familyName("ZZ99ZZ9", "")))
+	if a != nil || d != nil || err != nil {
+		t.Errorf("Read() of a prefix without data = %v, %v, %v", a, d, err)
+	}
+}
+
+func TestRestClientPin(t *testing.T) {
+	s := NewMemoryState()
+	putEntries(s, 3)
+	api := NewFakeRestAPI(s)
+	defer api.Close()
+
+	r := &RestClient{URL: api.URL}
+	pinned, err := r.Pin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned.(*RestClient).Head != "fake-0" || r.Head != "" {
+		t.Errorf("Pin() = head %q, left %q, want a copy at fake-0", pinned.(*RestClient).Head, r.Head)
+	}
+
+	// pinning a pinned client keeps its head, even once the chain moved
+	s.blockNum++
+	again, err := pinned.(*RestClient).Pin()
+	if err != nil || again.(*RestClient).Head != "fake-0" {
+		t.Errorf("Pin() of a pinned client = %v, %v", again, err)
+	}
+	latest, err := r.Pin()
+	if err != nil || latest.(*RestClient).Head != "fake-1" {
+		t.Errorf("Pin() after a block = %v, %v", latest, err)
+	}
+}
+
+func TestPinState(t *testing.T) {
+	s, restore := useMemoryState()
+	defer restore()
+
+	// the memory state doesn't pin
+	state, err := PinState()
+	if err != nil || state != StateReader(s) {
+		t.Errorf("PinState() of a MemoryState = %v, %v", state, err)
+	}
+
+	api := NewFakeRestAPI(s)
+	defer api.Close()
+	SetStateBackend(&RestClient{URL: api.URL}, s)
+	state, err = PinState()
+	if err != nil || state.(*RestClient).Head != "fake-0" {
+		t.Errorf("PinState() of a RestClient = %v, %v", state, err)
+	}
+}
@@ -1,77 +1,268 @@
 package core
 
 import (
-	"encoding/json"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Knetic/govaluate"
 
 	c "../common"
+	pb "../protos"
+	v "../verification"
 )
 
-// SortConflicts takes group rules and individual rules, for example, and overrule the group rule when conflict is detected. In general, rules in second array overrule those in first. Problem: how to detect conflicting rules? one idea: rules concerning the exact same quantities and only those (for example, initiator and amount and nothing else) should be solved for conflicts (using SAT?)
-func SortConflicts(m map[string][]ARule) []ARule {
-	// TODO TODO. z3 (?) or 2 rules that use exact same parameters or maybe one's parameters is a subset of the other. rules in "spec" (specific) override those in "gen" (generic). so map m expected to have two keys
+// SortConflicts takes group rules and individual rules, for example, and overrule the group rule when conflict is detected. rules in "spec" (specific) override those in "gen" (generic), so map m is expected to have those two keys. A specific rule overrides a generic rule when both involve at least one common variable and z3 finds an assignment under which both rules fire and don't return the same thing, unless the generic rule is final or of a higher priority. The overridden generic rule is kept but guarded so it only fires when none of the specific rules that override it do. Returns the resolved rules and the hashes of the generic rules that were shadowed
+func SortConflicts(m map[string][]ARule) ([]ARule, []string) {
+	resolved := make([]ARule, 0, len(m["gen"])+len(m["spec"]))
+	shadowed := make([]string, 0)
+	for _, g := range m["gen"] {
+		overriders := make([]string, 0) // conditions for the specific rules that override g to fire
+		for _, s := range m["spec"] {
+			if shadows(&s, &g) {
+				overriders = append(overriders, "("+s.Rule+") != 'nil'")
+			}
+		}
+
+		if len(overriders) == 0 {
+			resolved = append(resolved, g)
+			continue
+		}
+
+		shadowed = append(shadowed, g.RuleHash)
+		// note we keep the hash of the generic rule so a "deny" from the guarded rule is still reported under the rule the user set
+		guarded := "(" + strings.Join(overriders, " || ") + ") ? 'nil' : (" + g.Rule + ")"
+		resolved = append(resolved, ARule{Rule: guarded, RuleHash: g.RuleHash, ValidFrom: g.ValidFrom, ValidUntil: g.ValidUntil, Priority: g.Priority, Override: g.Override})
+	}
+
+	return append(resolved, m["spec"]...), shadowed
+}
+
+// shadows says whether the specific rule s overrides the generic rule g in SortConflicts(): final rules and rules of a higher priority than s are never overridden, nor rules never in force at the same time as s
+func shadows(s, g *ARule) bool {
+	return !g.Final() && s.Priority >= g.Priority && coexist(s, g) && overrides(s, g)
+}
+
+// names the rules are bound to in the SMT program built by overrides()
+const (
+	specOutcome = "o_spec"
+	genOutcome  = "o_gen"
+	clash       = "clash"
+)
+
+// overrides says whether the specific rule s takes precedence over the generic rule g. rules we can't translate to SMT are never considered in conflict: both will fire, which is what happened before we had conflict resolution
+func overrides(s, g *ARule) bool {
+	ts, err := v.Translate(s.Rule)
+	if err != nil {
+		return false
+	}
+	tg, err := v.Translate(g.Rule)
+	if err != nil {
+		return false
+	}
+	if !fastIntersect(ts.Vars, tg.Vars) {
+		return false
+	}
+
+	program, err := v.CreateNamedSMTprogram(map[string]string{specOutcome: s.Rule, genOutcome: g.Rule})
+	if err != nil {
+		return false
+	}
+	program += v.DefineBool(clash, "(and "+v.Fires(specOutcome)+" "+v.Fires(genOutcome)+" (distinct "+specOutcome+" "+genOutcome+"))")
 
-	return append(m["gen"], m["spec"]...) // so just concatenate until we have a proper implementation
+	res, err := v.Check(program, []string{clash})
+	if err != nil {
+		return false
+	}
+
+	return res.Status == v.Sat
 }
 
-// ARule structure for storing rules. rules are required to be ternary expressions. Rule must be a ternary expression that returns output from NofM() in RuleFunctions() or "nil"
+// ARule structure for storing rules. rules are required to be ternary expressions. Rule must be a ternary expression that returns output from NofM() in RuleFunctions(), "deny", "allow" or "nil"
 type ARule struct {
-	Rule     string `json:"rule"`
-	RuleHash string `json:"rulehash"`
+	Rule       string `json:"rule"`
+	RuleHash   string `json:"rulehash"`
+	ValidFrom  int64  `json:"valid_from,omitempty"`  // unix time the rule comes into force. 0 for always
+	ValidUntil int64  `json:"valid_until,omitempty"` // unix time the rule expires, excluded. 0 for never
+	Priority   int    `json:"priority,omitempty"`    // the rules of the highest priority that fire decide, see evaluateRules()
+	Override   string `json:"override,omitempty"`    // OverrideFinal or OverrideOverridable. empty is overridable
+}
+
+// Override modes of a rule. final rules are never overridden by specific rules nor outranked by rules of a higher priority. only bank admins set them
+const (
+	OverrideFinal       string = "final"
+	OverrideOverridable string = "overridable"
+)
+
+// Final whether the rule can't be overridden
+func (r *ARule) Final() bool {
+	return r.Override == OverrideFinal
+}
+
+// Statuses of a rule with respect to its validity
+const (
+	RuleScheduled string = "scheduled"
+	RuleActive    string = "active"
+	RuleExpired   string = "expired"
+)
+
+// Status of the rule at time now
+func (r *ARule) Status(now time.Time) string {
+	switch {
+	case r.ValidFrom != 0 && now.Unix() < r.ValidFrom:
+		return RuleScheduled
+	case r.ValidUntil != 0 && now.Unix() >= r.ValidUntil:
+		return RuleExpired
+	}
+
+	return RuleActive
+}
+
+// coexist whether there's a time at which both rules are in force
+func coexist(a, b *ARule) bool {
+	return (a.ValidUntil == 0 || b.ValidFrom < a.ValidUntil) && (b.ValidUntil == 0 || a.ValidFrom < b.ValidUntil)
+}
+
+// activeRules the rules in force at time now
+func activeRules(rules []ARule, now time.Time) []ARule {
+	ret := make([]ARule, 0, len(rules))
+	for _, r := range rules {
+		if r.Status(now) == RuleActive {
+			ret = append(ret, r)
+		}
+	}
+
+	return ret
+}
+
+func checkOverride(override string) error {
+	if override != "" && override != OverrideFinal && override != OverrideOverridable {
+		return validationError("unknown override mode "+override+". expecting "+OverrideFinal+" or "+OverrideOverridable, nil)
+	}
+
+	return nil
 }
 
-// Evaluate the rule for the given parameters. Currently returns "nil" (yes, string) or output from rule function(s)
-func (r *ARule) Evaluate(m map[string]interface{}) interface{} {
-	rule, err := govaluate.NewEvaluableExpressionWithFunctions(r.Rule, RuleFunctions())
+// checkPriority priorities are stored as int32, see protos
+func checkPriority(priority int) error {
+	if priority < math.MinInt32 || priority > math.MaxInt32 {
+		return validationError("priority "+strconv.Itoa(priority)+" out of range", nil)
+	}
+
+	return nil
+}
+
+// checkValidity a rule must come into force before it expires
+func checkValidity(validFrom, validUntil int64) error {
+	if validFrom < 0 || validUntil < 0 || (validUntil != 0 && validUntil <= validFrom) {
+		return validationError("rule must be valid from a time before it is valid until", nil)
+	}
+
+	return nil
+}
+
+// Evaluate the rule for the given parameters. Currently returns "nil" (yes, string), "deny", "allow" or output from rule function(s)
+func (r *ARule) Evaluate(m map[string]interface{}, state StateReader) (interface{}, error) {
+	rule, err := c.ParseRule(r.Rule, evaluationFunctions(m, state))
 	if err != nil {
-		panic(err)
+		return nil, validationError("cannot parse rule "+r.RuleHash, err)
 	}
 	result, err := rule.Evaluate(m)
 	if err != nil {
-		panic(err)
+		return nil, validationError("cannot evaluate rule "+r.RuleHash, err)
+	}
+
+	return result, nil
+}
+
+// the rule functions for evaluating rules on the banking transaction in m: spend and calendar functions are bound to its account and to its time, see bindClock()
+func evaluationFunctions(m map[string]interface{}, state StateReader) map[string]govaluate.ExpressionFunction {
+	fs := RuleFunctions()
+	if sourceAccount, ok := m["SourceAccount"].(string); ok {
+		now := clockOf(m)
+		bound := map[string]govaluate.ExpressionFunction{
+			"SpendSince":    spendSince(sourceAccount, now, state),
+			"IsHoliday":     isHoliday(sourceAccount, now, state),
+			"IsBusinessDay": isBusinessDay(sourceAccount, now, state),
+		}
+		for name, bf := range bound {
+			f, _ := c.LookupRuleFunction(name)
+			f.Func = bf
+			fs[f.Name] = f.Checked()
+		}
 	}
 
-	return result
+	return fs
 }
 
 // NewRule constructs new rule
-func NewRule(r string, ruleHash string) ARule {
+func NewRule(r string, ruleHash string) (ARule, error) {
 	ret := ARule{Rule: r, RuleHash: ruleHash}
 
 	// TODO checking if parameters are valid. costly?
-	rule, err := govaluate.NewEvaluableExpressionWithFunctions(r, RuleFunctions())
+	rule, err := c.ParseRule(r, RuleFunctions())
 	if err != nil {
-		panic(err)
+		return ret, validationError("cannot parse rule", err)
 	}
+	// every variable must be bound when the rule is evaluated, see common.RegisterRuleVariable()
 	expparams := rule.Vars()
 	for _, v := range expparams {
-		if !c.RuleVariablesSet[v] {
-			panic("wrong variable")
+		if _, ok := c.LookupRuleVariable(v); !ok {
+			return ret, validationError("unknown rule variable "+v, nil)
+		}
+	}
+
+	// govaluate returns nil for a ? without its :, which is no outcome evaluateRules can take
+	ternaries := 0
+	for _, t := range rule.Tokens() {
+		if t.Kind == govaluate.TERNARY && t.Value == "?" {
+			ternaries++
+		} else if t.Kind == govaluate.TERNARY && t.Value == ":" {
+			ternaries--
 		}
 	}
+	if ternaries != 0 {
+		return ret, validationError("every ? of a rule must have its : (else branch)", nil)
+	}
 
 	// necessary (but not sufficient) condition for r to be ternary and for it to return "nil" when predicate is false and therefore no action is required
 	if !strings.Contains(r, ":") || !strings.Contains(r, "?") || !strings.Contains(r, "nil") {
-		panic("rule must be a ternary expression that returns 'nil' (yes, string) when predicate is false")
+		return ret, validationError("rule must be a ternary expression that returns 'nil' (yes, string) when predicate is false", nil)
 	}
 
-	return ret
+	return ret, nil
 }
 
-func unmarshalRules(rules [][]byte) (accountRules []ARule) {
-	accountRules = make([]ARule, len(rules))
+// usesBinding whether one of rules uses a rule variable bound to bind, e.g., common.BindCoreBanking. rules that don't parse use none
+func usesBinding(rules []ARule, bind string) bool {
+	for _, r := range rules {
+		rule, err := c.ParseRule(r.Rule, RuleFunctions())
+		if err != nil {
+			continue
+		}
+		for _, name := range rule.Vars() {
+			if v, ok := c.LookupRuleVariable(name); ok && v.Bind == bind {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func unmarshalRules(rules [][]byte) ([]ARule, error) {
+	accountRules := make([]ARule, len(rules))
 	for i, rule := range rules {
-		var t ARule
-		err := json.Unmarshal(rule, &t)
+		var t pb.Rule
+		err := decodeState(stateRule, rule, &t)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
-		accountRules[i] = t
+		accountRules[i] = ARule{Rule: t.Rule, RuleHash: t.RuleHash, ValidFrom: t.ValidFrom, ValidUntil: t.ValidUntil, Priority: int(t.Priority), Override: t.Override}
 	}
 
-	return
+	return accountRules, nil
 }
 
 func tabulateRules(rules []ARule) (ruleHashes []string, accountRules []string) {
@@ -84,3 +275,12 @@ func tabulateRules(rules []ARule) (ruleHashes []string, accountRules []string) {
 
 	return
 }
+
+// formatValidity t, a ValidFrom or ValidUntil, as RFC 3339. "" when unbounded
+func formatValidity(t int64) string {
+	if t == 0 {
+		return ""
+	}
+
+	return time.Unix(t, 0).Format(time.RFC3339)
+}
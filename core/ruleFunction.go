@@ -1,7 +1,11 @@
 package core
 
 import (
+	"errors"
+
 	"github.com/Knetic/govaluate"
+
+	c "../common"
 )
 
 // helper function needed below.
@@ -36,36 +40,71 @@ func fastIntersect(a, b []string) bool {
 	return false
 }
 
-func aggregateSpend(args ...interface{}) (interface{}, error) {
-	// go to core banking system to get history of transactions and aggregate
-	// to be added to RuleFunctions() output after implementation
-
-	return nil, nil
+func init() {
+	c.RegisterRuleFunction(c.RuleFunction{
+		Name:    "NofM",
+		Args:    []string{c.SortReal, c.SortString},
+		Returns: c.SortOutcome,
+		Func:    nofM,
+	})
+	c.RegisterRuleFunction(c.RuleFunction{
+		Name:    "SpendSince",
+		Args:    []string{c.SortString},
+		Returns: c.SortReal,
+		Func:    aggregateSpend,
+	})
+	c.RegisterRuleFunction(c.RuleFunction{
+		Name:    "IsHoliday",
+		Args:    []string{},
+		Returns: c.SortBool,
+		Func:    calendarFunction,
+	})
+	c.RegisterRuleFunction(c.RuleFunction{
+		Name:    "IsBusinessDay",
+		Args:    []string{},
+		Returns: c.SortBool,
+		Func:    calendarFunction,
+	})
+	c.RegisterRuleFunction(c.RuleFunction{
+		Name:    "AccountBalance",
+		Args:    []string{c.SortString},
+		Returns: c.SortReal,
+		Func:    accountBalance,
+	})
 }
 
-func accountBalance(args ...interface{}) (interface{}, error) {
-	// get the account balance from the CBS
-	// to be added to RuleFunctions() output after implementation
+func nofM(args ...interface{}) (interface{}, error) {
+	a := args[0] // min number of signatures, i.e., N
+	b := args[1] // string with comma-separated list of all authorised signers by ID (i.e., initiator), M is the len() of the list
 
-	return nil, nil
+	return []interface{}{a, b}, nil
 }
 
-// RuleFunctions the functions allowable in rule expressions
-func RuleFunctions() map[string]govaluate.ExpressionFunction {
-
-	ruleFunctions := map[string]govaluate.ExpressionFunction{
-		"NofM": func(args ...interface{}) (interface{}, error) {
+// aggregateSpend is SpendSince() before it's bound to the account of a banking transaction, see evaluationFunctions() and spendSince(). rules are only evaluated on banking transactions so we shouldn't get here
+func aggregateSpend(args ...interface{}) (interface{}, error) {
+	return nil, errors.New("SpendSince() needs the account of a banking transaction")
+}
 
-			a := args[0] // min number of signatures, i.e., N
-			b := args[1] // string with comma-separated list of all authorised signers by ID (i.e., initiator), M is the len() of the list
+// calendarFunction is IsHoliday() and IsBusinessDay() before they're bound to the account and the time of a banking transaction, see evaluationFunctions(), isHoliday() and isBusinessDay()
+func calendarFunction(args ...interface{}) (interface{}, error) {
+	return nil, errors.New("calendar functions need the account of a banking transaction")
+}
 
-			return []interface{}{a, b}, nil
-		},
+// accountBalance returns the balance of the account passed as argument, e.g., AccountBalance('ZY12ABC') > 0, from the core banking system. Note: the balance of the source account of the banking transaction is in the Balance variable
+func accountBalance(args ...interface{}) (interface{}, error) {
+	if coreBanking == nil {
+		return nil, errors.New("AccountBalance() needs a core banking system")
 	}
 
-	// now add the regular rule functions
-	for k, v := range RuleFunctions() {
-		ruleFunctions[k] = v
+	return coreBanking.GetBalance(args[0].(string))
+}
+
+// RuleFunctions the functions allowable in rule expressions, i.e., those in the registry (see common.RegisterRuleFunction()), with their arguments checked
+func RuleFunctions() map[string]govaluate.ExpressionFunction {
+	ruleFunctions := make(map[string]govaluate.ExpressionFunction)
+	for _, name := range c.RuleFunctionNames() {
+		f, _ := c.LookupRuleFunction(name)
+		ruleFunctions[name] = f.Checked()
 	}
 
 	return ruleFunctions
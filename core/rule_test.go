@@ -0,0 +1,125 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestRuleStatus(t *testing.T) {
+	now := at("2026-03-01T12:00:00Z").Unix()
+	tests := []struct {
+		name       string
+		validFrom  int64
+		validUntil int64
+		want       string
+	}{
+		{"unbounded", 0, 0, RuleActive},
+		{"in force", now - 60, now + 60, RuleActive},
+		{"comes into force now", now, 0, RuleActive},
+		{"scheduled", now + 1, 0, RuleScheduled},
+		{"expires now", 0, now, RuleExpired},
+		{"expired", now - 120, now - 60, RuleExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := ARule{ValidFrom: tt.validFrom, ValidUntil: tt.validUntil}
+			if got := r.Status(at("2026-03-01T12:00:00Z")); got != tt.want {
+				t.Errorf("Status() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortConflicts(t *testing.T) {
+	deny := ARule{Rule: "Amount > 100 ? 'deny' : 'nil'", RuleHash: "gen"}
+	allow := ARule{Rule: "Amount > 50 ? 'allow' : 'nil'", RuleHash: "spec"}
+	now := at("2026-03-01T12:00:00Z").Unix()
+
+	tests := []struct {
+		name     string
+		gen      func(ARule) ARule
+		spec     func(ARule) ARule
+		shadowed bool
+	}{
+		{"specific overrides generic", nil, nil, true},
+		{"final", func(r ARule) ARule { r.Override = OverrideFinal; return r }, nil, false},
+		{"explicitly overridable", func(r ARule) ARule { r.Override = OverrideOverridable; return r }, nil, true},
+		{"generic of a higher priority", func(r ARule) ARule { r.Priority = 2; return r }, nil, false},
+		{"specific of a higher priority", nil, func(r ARule) ARule { r.Priority = 2; return r }, true},
+		{"never in force together", func(r ARule) ARule { r.ValidUntil = now; return r }, func(r ARule) ARule { r.ValidFrom = now; return r }, false},
+		{"in force together", func(r ARule) ARule { r.ValidUntil = now + 1; return r }, func(r ARule) ARule { r.ValidFrom = now; return r }, true},
+		{"no common variable", nil, func(r ARule) ARule { r.Rule = "Recipient == 'bob' ? 'allow' : 'nil'"; return r }, false},
+		{"same outcome", nil, func(r ARule) ARule { r.Rule = "Amount > 50 ? 'deny' : 'nil'"; return r }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, s := deny, allow
+			if tt.gen != nil {
+				g = tt.gen(g)
+			}
+			if tt.spec != nil {
+				s = tt.spec(s)
+			}
+
+			resolved, shadowed := SortConflicts(map[string][]ARule{"gen": {g}, "spec": {s}})
+			if len(resolved) != 2 || resolved[0].RuleHash != g.RuleHash || resolved[1] != s {
+				t.Fatalf("SortConflicts() resolved %v", resolved)
+			}
+			if got := len(shadowed) == 1 && shadowed[0] == g.RuleHash; got != tt.shadowed {
+				t.Errorf("SortConflicts() shadowed %v", shadowed)
+			}
+			if got := resolved[0].Rule != g.Rule; got != tt.shadowed {
+				t.Errorf("SortConflicts() resolved the generic rule to %s", resolved[0].Rule)
+			}
+		})
+	}
+}
+
+// the generic rule is guarded, not dropped: it still fires when the specific rules that override it don't
+func TestSortConflictsGuard(t *testing.T) {
+	g := ARule{Rule: "Amount > 100 ? 'deny' : 'nil'", RuleHash: "gen"}
+	s := ARule{Rule: "Amount > 100 && Recipient == 'bob' ? 'allow' : 'nil'", RuleHash: "spec"}
+	resolved, _ := SortConflicts(map[string][]ARule{"gen": {g}, "spec": {s}})
+
+	tests := []struct {
+		recipient string
+		want      string
+	}{
+		{"bob", "nil"},
+		{"eve", "deny"},
+	}
+	for _, tt := range tests {
+		res, err := resolved[0].Evaluate(map[string]interface{}{"Amount": 150.0, "Recipient": tt.recipient}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != tt.want {
+			t.Errorf("guarded rule for %s = %v, want %s", tt.recipient, res, tt.want)
+		}
+	}
+}
+
+func TestNewRule(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		valid bool
+	}{
+		{"ternary", "Amount > 100 ? 'deny' : 'nil'", true},
+		{"nested ternary", "Amount > 100 ? 'deny' : (Amount > 50 ? 'allow' : 'nil')", true},
+		{"ternary without else", "Amount > 100 ? 'deny'", false},
+		{"nested ternary without else", "Amount > 100 ? 'deny' : (Amount > 50 ? 'nil')", false},
+		{"unknown variable", "Fee > 100 ? 'deny' : 'nil'", false},
+		{"not a ternary", "Amount > 100", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRule(tt.rule, "")
+			if (err == nil) != tt.valid {
+				t.Errorf("NewRule(%q) = %v, want valid %v", tt.rule, err, tt.valid)
+			}
+		})
+	}
+}
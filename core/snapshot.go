@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// StateSnapshot is a local copy of (part of) the state, addresses mapped to their data. It's for running rule queries offline, against the state as it was, e.g., when replaying transactions, see Replay()
+type StateSnapshot map[string][]byte
+
+// LoadStateSnapshot reads a snapshot from a file holding the responses from the rest api state endpoint, one per page, for instance curl http://localhost:8008/state?address=<namespace of the account> > snapshot.json, then curl <paging.next of the last response> >> snapshot.json while there is one. A snapshot missing pages is rejected: rules left out would change decisions silently
+func LoadStateSnapshot(fileName string) (StateSnapshot, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, validationError("cannot read snapshot", err)
+	}
+	defer f.Close()
+
+	s := make(StateSnapshot)
+	dec := json.NewDecoder(f)
+	pages, next := 0, ""
+	for {
+		var body StateRespBody
+		err = dec.Decode(&body)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, validationError("malformed snapshot", err)
+		}
+		addresses, data, err := parseStateBody(&body)
+		if err != nil {
+			return nil, err
+		}
+		for i, a := range addresses {
+			s[a] = data[i]
+		}
+		pages, next = pages+1, body.Paging.Next
+	}
+	if pages == 0 {
+		return nil, validationError("empty snapshot", nil)
+	}
+	if next != "" {
+		return nil, validationError("snapshot is missing pages, the next one is at "+next, nil)
+	}
+
+	return s, nil
+}
+
+// Read is SubmitStateReq() on the snapshot: the entries whose address starts with address, sorted by address as the rest api does
+func (s StateSnapshot) Read(address string) ([]string, [][]byte, error) {
+	return readPrefix(s, address)
+}
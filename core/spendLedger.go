@@ -0,0 +1,174 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	c "../common"
+	pb "../protos"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// PayloadRecordSpend records an allowed banking transaction in the spend ledger of the account. Note: like PayloadSetPendingTx it is only defined here because a client never initiates it: the bank does after a query auth is allowed
+type PayloadRecordSpend struct {
+	SourceAccount string
+	Amount        float64
+	Time          int64 // unix time at which the banking transaction was allowed. set by the bank so that all validators record the same thing
+}
+
+// SpendEntry is a banking transaction in the spend ledger as written by 0.1. stored as a protobuf SpendLedger since, see stateSpendLedger
+type SpendEntry struct {
+	Time   int64   `json:"time"`
+	Amount float64 `json:"amount"`
+}
+
+const ledgerNamespace = "03"
+
+// rolling windows SpendSince() takes besides durations like '24h'. a month is 30 days
+var spendWindows = map[string]time.Duration{
+	"hour":  time.Hour,
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// entries older than the longest window are dropped from the ledger when it's updated
+var ledgerRetention = spendWindows["month"]
+
+// Apply applier for recording spend
+func (*PayloadRecordSpend) Apply(pl []byte, context StateContext) error {
+	var p PayloadRecordSpend
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return validationError("malformed record spend payload", err)
+	}
+
+	return recordSpend(context, spendLedger(p.SourceAccount), p.Amount, p.Time)
+}
+
+// recordSpend appends to the ledger at address. Note: also used when the last signature of a pending tx is added
+func recordSpend(context StateContext, address string, amount float64, t int64) error {
+	m, err := context.GetState([]string{address})
+	if err != nil {
+		return stateError("error reading spend ledger", err)
+	}
+
+	var ledger pb.SpendLedger
+	if len(m[address]) != 0 {
+		err = decodeState(stateSpendLedger, m[address], &ledger)
+		if err != nil {
+			return err
+		}
+	}
+
+	// keep the ledger short
+	cutoff := t - int64(ledgerRetention.Seconds())
+	kept := make([]*pb.SpendEntry, 0, len(ledger.Entries)+1)
+	for _, e := range ledger.Entries {
+		if e.Time > cutoff {
+			kept = append(kept, e)
+		}
+	}
+	kept = append(kept, &pb.SpendEntry{Time: t, Amount: amount})
+
+	enc, err := encodeState(&pb.SpendLedger{Entries: kept})
+	if err != nil {
+		return err
+	}
+
+	addresses, err := context.SetState(map[string][]byte{address: enc})
+	if err != nil || len(addresses) == 0 {
+		return stateError("error recording spend", err)
+	}
+
+	return nil
+}
+
+// spendSince returns SpendSince() for rules evaluated on banking transactions on sourceAccount at time now: the sum of the amounts in the ledger in state over the window passed as argument, e.g., SpendSince('day') or SpendSince('24h')
+func spendSince(sourceAccount string, now time.Time, state StateReader) func(args ...interface{}) (interface{}, error) {
+	return func(args ...interface{}) (interface{}, error) {
+		window, err := spendWindow(args[0].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		_, data, err := state.Read(spendLedger(sourceAccount))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return 0.0, nil
+		}
+		var ledger pb.SpendLedger
+		err = decodeState(stateSpendLedger, data[0], &ledger)
+		if err != nil {
+			return nil, err
+		}
+
+		// entries after now are left out: transactions replayed at an earlier time mustn't see the spend that followed
+		since := now.Add(-window).Unix()
+		total := 0.0
+		for _, e := range ledger.Entries {
+			if e.Time > since && e.Time <= now.Unix() {
+				total += e.Amount
+			}
+		}
+
+		return total, nil
+	}
+}
+
+func spendWindow(w string) (time.Duration, error) {
+	window, ok := spendWindows[w]
+	if !ok {
+		var err error
+		window, err = time.ParseDuration(w)
+		if err != nil {
+			return 0, errors.New("unknown SpendSince() window " + w)
+		}
+	}
+	if window <= 0 || window > ledgerRetention {
+		return 0, errors.New("SpendSince() window must be positive and at most " + strconv.Itoa(int(ledgerRetention.Hours())) + "h")
+	}
+
+	return window, nil
+}
+
+// Note this lives here and not in queryAuthorisation.go with createSetPendingTx() because of the state address
+func createRecordSpendTx(sourceAccount string, amount float64, t int64) (*transaction_pb2.Transaction, error) {
+	payloadEnc, err := c.EncodePayload(PayloadRecordSpend{
+		SourceAccount: sourceAccount,
+		Amount:        amount,
+		Time:          t,
+	})
+	if err != nil {
+		return nil, internalError("cannot encode record spend payload", err)
+	}
+
+	bankPubKey, signer := GetBankAuthTools()
+	signedPayload := c.SignedPayload{
+		Version:       FamilyVersion,
+		SourceAccount: sourceAccount,
+		Type:          "record_spend",
+		SignerPubKey:  bankPubKey.AsBytes(),
+		Signature:     signer.Sign(payloadEnc),
+		Payload:       payloadEnc,
+	}
+
+	outputs := []string{spendLedger(sourceAccount)}
+	inputs := outputs
+	dependencies := []string{}
+	// Note: like pending transactions, the spend ledger does not take permission tags
+	familyName := sourceAccount
+
+	return CreateTransaction(&signedPayload, familyName, inputs, outputs, dependencies)
+}
+
+// address of the spend ledger of the account. one leaf per account
+func spendLedger(sourceAccount string) string {
+	// NO permission tag for the spend ledger
+	root := Namespace(familyName(sourceAccount, "")) + ledgerNamespace
+	dummyString := "spend ledger lives here"
+	return CheckLength(root + HexdigestStr(dummyString)[:AddressLength-len(root)])
+}
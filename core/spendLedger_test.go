@@ -0,0 +1,76 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	c "../common"
+	pb "../protos"
+)
+
+func spendAmounts(t *testing.T, s *MemoryState) []float64 {
+	_, data, err := s.Read(spendLedger(testAccount))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	var ledger pb.SpendLedger
+	err = decodeState(stateSpendLedger, data[0], &ledger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ret []float64
+	for _, e := range ledger.Entries {
+		ret = append(ret, e.Amount)
+	}
+
+	return ret
+}
+
+// allowed banking transactions count towards SpendSince(), denied ones don't
+func TestQueryAuthRecordsSpend(t *testing.T) {
+	defer useBankKeys(t)()
+	s, restore := useMemoryState()
+	defer restore()
+	putRule(t, s, c.DefaultGroupName, ARule{Rule: "SpendSince('day') + Amount > 1000 ? 'deny' : 'nil'"})
+
+	for _, tt := range []struct {
+		amount float64
+		action string
+	}{{600, "allow"}, {600, "deny"}, {300, "allow"}, {200, "deny"}} {
+		pl, err := c.EncodePayload(c.PayloadQueryAuth{SourceAccount: testAccount, Initiator: "CD34YG4", Amount: tt.amount})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, err := (&PayloadQueryAuth{}).Handle(pl, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ret["action"] != tt.action {
+			t.Errorf("query_auth of %v = %v, want %s", tt.amount, ret["action"], tt.action)
+		}
+	}
+
+	if got := spendAmounts(t, s); !reflect.DeepEqual(got, []float64{600, 300}) {
+		t.Errorf("spend ledger = %v, want the allowed transactions", got)
+	}
+}
+
+func TestRecordSpendRetention(t *testing.T) {
+	s := NewMemoryState()
+	start := at("2026-03-01T12:00:00Z").Unix()
+	for i, amount := range []float64{1, 2, 3} {
+		// 20 days apart: the first entry is older than the longest window when the third is recorded
+		err := recordSpend(s, spendLedger(testAccount), amount, start+int64(i)*20*24*3600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := spendAmounts(t, s); !reflect.DeepEqual(got, []float64{2, 3}) {
+		t.Errorf("spend ledger = %v, want the entries of the last month", got)
+	}
+}
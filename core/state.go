@@ -0,0 +1,373 @@
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	c "../common"
+	tpr "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// StateReader reads the state as the rest api state endpoint does: the addresses starting with address and their data, sorted by address. No data is not an error
+type StateReader interface {
+	Read(address string) ([]string, [][]byte, error)
+}
+
+// StateWriter submits transactions to the ledger. SubmitBatch() submits transactions in one batch, committed all or none, and returns its id without waiting. Status() returns the status of the batch, once it's no longer PENDING if wait is set. the errors are for the submission and the query themselves: invalid transactions are in the status
+type StateWriter interface {
+	SubmitBatch(txs []*tpr.Transaction) (string, error)
+	Status(id string, wait bool) (*BatchStatus, error)
+}
+
+// StateContext is the state as appliers see it: the context the validator hands the transaction processor, or the one MemoryState applies transactions in
+type StateContext interface {
+	GetState(addresses []string) (map[string][]byte, error)
+	SetState(pairs map[string][]byte) ([]string, error)
+	DeleteState(addresses []string) ([]string, error)
+}
+
+// where core reads the state and submits transactions to: the sawtooth rest api unless SetStateBackend() is called
+var (
+	backendReader StateReader = &RestClient{URL: c.RestAPI, PageLimit: c.RestAPIPageLimit}
+	backendWriter StateWriter = &RestClient{URL: c.RestAPI, PageLimit: c.RestAPIPageLimit}
+)
+
+// invalidateReads tells the state backend we changed the state, if it keeps a copy of it, e.g., Indexer
+func invalidateReads() {
+	if i, ok := backendReader.(interface{ Invalidate() }); ok {
+		i.Invalidate()
+	}
+}
+
+// PinState returns the state backend as it is now, for a request to read all its state at the same block: a rule query mustn't see a rule set and not the group it's set on, a permission check mustn't see a role revoked and not the next grant. Backends that change in place without a Pin() are returned as they are, e.g., MemoryState
+func PinState() (StateReader, error) {
+	if p, ok := backendReader.(statePinner); ok {
+		return p.Pin()
+	}
+
+	return backendReader, nil
+}
+
+// statePinner is implemented by the state backends that can pin their state, e.g., RestClient pins the head of the chain
+type statePinner interface {
+	Pin() (StateReader, error)
+}
+
+// SetStateBackend plugs in the state core reads and the ledger it submits transactions to, e.g., a MemoryState for both in unit tests, or another ledger altogether
+func SetStateBackend(r StateReader, w StateWriter) {
+	backendReader = r
+	backendWriter = w
+}
+
+// MemoryState is a state backend held in memory, addresses mapped to their data. SubmitBatch() applies transactions right away, with the checks of the transaction processor, so core can be tested without a validator. Use NewMemoryState(), the zero value has no maps
+type MemoryState struct {
+	mu       sync.Mutex
+	data     map[string][]byte
+	statuses map[string]BatchStatus // batch id => status
+	blockNum uint64                 // committed batches, each is a block
+}
+
+// NewMemoryState returns an empty MemoryState
+func NewMemoryState() *MemoryState {
+	return &MemoryState{
+		data:     make(map[string][]byte),
+		statuses: make(map[string]BatchStatus),
+	}
+}
+
+// Read is SubmitStateReq() on the memory state: the entries whose address starts with address, sorted by address as the rest api does
+func (s *MemoryState) Read(address string) ([]string, [][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return readPrefix(s.data, address)
+}
+
+// clone a copy of the data of the state
+func (s *MemoryState) clone() *MemoryState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := NewMemoryState()
+	for a, d := range s.data {
+		ret.data[a] = d
+	}
+
+	return ret
+}
+
+// SubmitBatch applies the transactions to the memory state, all or nothing. the id of the batch is the hash of the ids of its transactions
+func (s *MemoryState) SubmitBatch(txs []*tpr.Transaction) (string, error) {
+	ids := make([]string, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.HeaderSignature
+	}
+	id := HexdigestStr(strings.Join(ids, ","))
+
+	return id, s.apply(id, txs)
+}
+
+// Status of a batch applied to the memory state. batches are never pending, wait is ignored
+func (s *MemoryState) Status(id string, wait bool) (*BatchStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.statuses[id]
+	if !ok {
+		status = BatchStatus{ID: id, Status: "UNKNOWN", InvalidTransactions: make([]InvalidTransaction, 0)}
+	}
+
+	return &status, nil
+}
+
+// apply the batch with id and record its status: INVALID if the transaction processor would reject one of its transactions. other errors are returned, the status of the batch is then unknown as the validator would retry it
+func (s *MemoryState) apply(id string, txs []*tpr.Transaction) error {
+	status := BatchStatus{ID: id, Status: "COMMITTED", InvalidTransactions: make([]InvalidTransaction, 0)}
+	txID, err := s.applyBatch(txs)
+	if err != nil {
+		if !rejected(err) {
+			return err
+		}
+		status.Status = "INVALID"
+		status.InvalidTransactions = append(status.InvalidTransactions, InvalidTransaction{ID: txID, Message: err.Error()})
+	}
+
+	s.mu.Lock()
+	s.statuses[id] = status
+	if status.Status == "COMMITTED" {
+		s.blockNum++
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// rejected tells the errors for which the transaction processor rejects transactions for good, see tprocessor
+func rejected(err error) bool {
+	k := KindOf(err)
+	return k == KindValidation || k == KindAuthorization || k == KindNotFound
+}
+
+// applyBatch applies the transactions in order on a copy of the state, which replaces the state if they all succeed. returns the id of the transaction that failed, if any
+func (s *MemoryState) applyBatch(txs []*tpr.Transaction) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := make(map[string][]byte, len(s.data))
+	for a, d := range s.data {
+		data[a] = d
+	}
+
+	for _, tx := range txs {
+		header, err := txHeader(tx)
+		if err != nil {
+			return tx.HeaderSignature, err
+		}
+		if header.PayloadSha512 != HexdigestB(tx.Payload) {
+			return tx.HeaderSignature, validationError("payload does not match transaction header", nil)
+		}
+
+		context := &memoryContext{data: data, inputs: header.Inputs, outputs: header.Outputs}
+		err = ApplyTransaction(header, tx.Payload, context)
+		if err != nil {
+			return tx.HeaderSignature, err
+		}
+	}
+	s.data = data
+
+	return "", nil
+}
+
+// GetState as the validator does it: addresses without data are left out
+func (s *MemoryState) GetState(addresses []string) (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return getState(s.data, addresses), nil
+}
+
+// SetState writes straight to the memory state, e.g., to set up a unit test
+func (s *MemoryState) SetState(pairs map[string][]byte) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return setState(s.data, pairs), nil
+}
+
+// DeleteState returns the addresses that had data
+func (s *MemoryState) DeleteState(addresses []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return deleteState(s.data, addresses), nil
+}
+
+// memoryContext is the context MemoryState applies a transaction in: a copy of the state, restricted like the validator restricts the context to the inputs and outputs of the transaction
+type memoryContext struct {
+	data    map[string][]byte
+	inputs  []string
+	outputs []string
+}
+
+func (m *memoryContext) GetState(addresses []string) (map[string][]byte, error) {
+	err := checkInScope(addresses, m.inputs, "input")
+	if err != nil {
+		return nil, err
+	}
+
+	return getState(m.data, addresses), nil
+}
+
+func (m *memoryContext) SetState(pairs map[string][]byte) ([]string, error) {
+	addresses := make([]string, 0, len(pairs))
+	for a := range pairs {
+		addresses = append(addresses, a)
+	}
+	err := checkInScope(addresses, m.outputs, "output")
+	if err != nil {
+		return nil, err
+	}
+
+	return setState(m.data, pairs), nil
+}
+
+func (m *memoryContext) DeleteState(addresses []string) ([]string, error) {
+	err := checkInScope(addresses, m.outputs, "output")
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteState(m.data, addresses), nil
+}
+
+// checkInScope every address must start with one of the inputs (or outputs) of the transaction. the validator fails the transaction otherwise, and so do we so that missing inputs show in unit tests
+func checkInScope(addresses, scope []string, what string) error {
+	for _, a := range addresses {
+		ok := false
+		for _, p := range scope {
+			if strings.HasPrefix(a, p) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return internalError("address "+a+" is not an "+what+" of the transaction", nil)
+		}
+	}
+
+	return nil
+}
+
+func readPrefix(data map[string][]byte, address string) ([]string, [][]byte, error) {
+	addresses := make([]string, 0)
+	for a := range data {
+		if strings.HasPrefix(a, address) {
+			addresses = append(addresses, a)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, nil, nil
+	}
+	sort.Strings(addresses)
+
+	ret := make([][]byte, len(addresses))
+	for i, a := range addresses {
+		ret[i] = data[a]
+	}
+
+	return addresses, ret, nil
+}
+
+func getState(data map[string][]byte, addresses []string) map[string][]byte {
+	m := make(map[string][]byte, len(addresses))
+	for _, a := range addresses {
+		if d, ok := data[a]; ok {
+			m[a] = d
+		}
+	}
+
+	return m
+}
+
+func setState(data map[string][]byte, pairs map[string][]byte) []string {
+	addresses := make([]string, 0, len(pairs))
+	for a, d := range pairs {
+		data[a] = d
+		addresses = append(addresses, a)
+	}
+
+	return addresses
+}
+
+func deleteState(data map[string][]byte, addresses []string) []string {
+	deleted := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		if _, ok := data[a]; ok {
+			delete(data, a)
+			deleted = append(deleted, a)
+		}
+	}
+
+	return deleted
+}
+
+// ApplyTransaction checks the transaction with header and payload, a json SignedPayload, and applies it to the state in context. It's what the transaction processor does with the transactions the validator sends it, and what MemoryState does with the ones submitted to it. Note: the lambda checked all this before wrapping the payload but batches can be submitted to the rest api directly, and the state may have changed since
+func ApplyTransaction(header *tpr.TransactionHeader, payload []byte, context StateContext) error {
+	var p c.SignedPayload
+	err := json.Unmarshal(payload, &p)
+	if err != nil {
+		return validationError("payload is not a signed payload", err)
+	}
+
+	pc, ok := LookupPayload(p.Type)
+	if !ok || pc.Applier == nil {
+		return validationError("unknown payload type "+p.Type, nil)
+	}
+	if !VerifySignature(p.Payload, p.Signature, p.SignerPubKey) {
+		return authorizationError("invalid signature for " + p.Type + " transaction")
+	}
+	// the payload must be on the account of this family, and of the version of the transaction
+	if header.FamilyName != FamilyName(p.SourceAccount, p.Type) {
+		return validationError("payload on account "+p.SourceAccount+" in transaction family "+header.FamilyName, nil)
+	}
+	err = CheckPayload(&p)
+	if err != nil {
+		return err
+	}
+	if header.FamilyVersion != VersionOf(&p) {
+		return validationError("payload of version "+p.Version+" in transaction of version "+header.FamilyVersion, nil)
+	}
+	ok, err = VerifyInitiatorKeyInContext(&p, context)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return authorizationError("key of " + p.Type + " transaction is not registered for its initiator")
+	}
+	ok, err = VerifyPermissionInContext(p.SourceAccount, p.Type, p.SignerPubKey, context)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return authorizationError("signer of " + p.Type + " transaction is not authorised")
+	}
+
+	return pc.Applier().Apply(p.Payload, &signedContext{StateContext: context, signerPubKey: p.SignerPubKey})
+}
+
+// signedContext is the context of a transaction along with the key its payload is signed with, for the appliers whose checks depend on the signer, e.g., only bank admins touch final rules
+type signedContext struct {
+	StateContext
+	signerPubKey []byte
+}
+
+// signerOf the key the payload applied in context is signed with. nil if context doesn't say, which no check should take for a bank admin
+func signerOf(context StateContext) []byte {
+	if sc, ok := context.(*signedContext); ok {
+		return sc.signerPubKey
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	c "../common"
+	tpr "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// a transaction writes its outputs only: the memory state fails it otherwise, as the validator does, and keeps none of its batch
+func TestMemoryStateScope(t *testing.T) {
+	defer useBankKeys(t)()
+	s := NewMemoryState()
+	bankPubKey, bank := GetBankAuthTools()
+
+	recipient := func(name string) *c.SignedPayload {
+		return signed(t, "set_recipient", &c.PayloadSetRecipient{SourceAccount: testAccount, Recipient: name, DestAccount: "12345678"}, bank, bankPubKey.AsBytes())
+	}
+	fn := familyName(testAccount, RecipientPermissionTag)
+	alice := recipientAccount(recipientRootStateAddress(testAccount), "alice", "12345678")
+	ok, err := CreateTransaction(recipient("alice"), fn, []string{alice}, []string{alice}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outOfScope, err := CreateTransaction(recipient("bob"), fn, []string{alice}, []string{alice}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.SubmitBatch([]*tpr.Transaction{ok, outOfScope})
+	if KindOf(err) != KindState || !strings.Contains(err.Error(), "not an output") {
+		t.Errorf("SubmitBatch() of a transaction writing outside its outputs = %v, want a state error", err)
+	}
+	if a, _, _ := s.Read(alice); len(a) != 0 {
+		t.Error("transaction of a failed batch applied")
+	}
+
+	if st := submit(t, s, ok); st.Status != "COMMITTED" {
+		t.Errorf("batch = %+v", st)
+	}
+	if st, _ := s.Status("unknown", false); st.Status != "UNKNOWN" {
+		t.Errorf("Status() of an unknown batch = %+v", st)
+	}
+}
+
+// the fake rest api takes batches from RestClient and applies them to its memory state
+func TestFakeRestAPI(t *testing.T) {
+	defer useBankKeys(t)()
+	s, restore := useMemoryState()
+	defer restore()
+	api := NewFakeRestAPI(s)
+	defer api.Close()
+	r := &RestClient{URL: api.URL}
+	SetStateBackend(r, r)
+
+	bankPubKey, bank := GetBankAuthTools()
+	_, pubKey := newSigner()
+	grant := &c.PayloadGrantRole{SourceAccount: testAccount, PermissionTag: RecipientPermissionTag, PubKey: hex.EncodeToString(pubKey), Role: RoleRuleSetter}
+	tx, err := (&PayloadGrantRole{}).WrapInTx(signed(t, "grant_role", grant, bank, bankPubKey.AsBytes()), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SubmitTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	roles, err := readRoles(permissionAddress(testAccount, RecipientPermissionTag, pubKey), leafReader(r))
+	if err != nil || len(roles) != 1 || roles[0] != RoleRuleSetter {
+		t.Errorf("roles = %v, %v", roles, err)
+	}
+
+	// rejected by the transaction processor: the signer is no admin
+	setter, setterPubKey := newSigner()
+	grant.PubKey = hex.EncodeToString(setterPubKey)
+	grant.Role = RoleBankAdmin
+	tx, err = CreateTransaction(signed(t, "grant_role", grant, setter, setterPubKey), familyName(testAccount, ""), nil, []string{permissionAddress(testAccount, RecipientPermissionTag, setterPubKey)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SubmitTx(tx)
+	if KindOf(err) != KindValidation || !strings.Contains(err.Error(), "not authorised") {
+		t.Errorf("SubmitTx() of a grant by a non admin = %v, want it rejected", err)
+	}
+	if s.blockNum != 1 {
+		t.Errorf("%d blocks, want the grant of the bank only", s.blockNum)
+	}
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	c "../common"
+	tpr "github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+)
+
+// PayloadBatchStatus methods implementation
+type PayloadBatchStatus c.PayloadBatchStatus
+
+// final statuses of batches, see BatchListener. UNKNOWN is also what we report when we gave up waiting
+var finalStatuses = []string{"COMMITTED", "INVALID", "UNKNOWN"}
+
+// BatchListener is told the final status of the batches submitted with SubmitTxAsync(), e.g., Webhook(). it's called on its own goroutine
+type BatchListener func(status *BatchStatus)
+
+var (
+	batchListenersMu sync.Mutex
+	batchListeners   []BatchListener
+)
+
+// AddBatchListener adds l to the listeners told the final status of batches
+func AddBatchListener(l BatchListener) {
+	batchListenersMu.Lock()
+	defer batchListenersMu.Unlock()
+
+	batchListeners = append(batchListeners, l)
+}
+
+// Webhook returns a listener posting the final status of batches as json to url. Note: it's a local stand-in for the event subscription of the validator: we wait on the status of the batches we submitted rather than subscribe to block commits
+func Webhook(url string) BatchListener {
+	return func(status *BatchStatus) {
+		b, err := json.Marshal(status)
+		if err != nil {
+			fmt.Printf("cannot encode status of batch %s: %v\n", status.ID, err)
+			return
+		}
+
+		resp, err := http.Post(url, "application/json", bytes.NewBuffer(b))
+		if err != nil {
+			fmt.Printf("cannot post status of batch %s: %v\n", status.ID, err)
+			return
+		}
+		resp.Body.Close()
+	}
+}
+
+// SubmitTx submits the transaction to the state backend and waits until it's committed. for the bank's own transactions, e.g., set_pending_tx, which the request that triggers them depends on
+func SubmitTx(tx *tpr.Transaction) (map[string]interface{}, error) {
+	id, err := backendWriter.SubmitBatch([]*tpr.Transaction{tx})
+	if err != nil {
+		return nil, err
+	}
+	status, err := backendWriter.Status(id, true)
+	if err != nil {
+		return nil, err
+	}
+	invalidateReads()
+	// the transaction processor rejected the transaction: we can't tell the kind of error it returned, its message is what we have
+	if status.Status == "INVALID" {
+		message := ""
+		if len(status.InvalidTransactions) != 0 {
+			message = status.InvalidTransactions[0].Message
+		}
+		return nil, validationError("transaction rejected", errors.New(message))
+	}
+	if status.Status != "COMMITTED" {
+		return nil, stateError("batch not committed, status="+status.Status, nil)
+	}
+
+	return map[string]interface{}{
+		"batch_id": id,
+	}, nil
+}
+
+// SubmitTxAsync submits the transaction to the state backend and returns the id of its batch right away. the final status goes to done, if not nil, then to the listeners, see AddBatchListener(). clients query it with batch_status requests
+func SubmitTxAsync(tx *tpr.Transaction, done func(status *BatchStatus)) (map[string]interface{}, error) {
+	id, err := submitAsync([]*tpr.Transaction{tx}, done)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"batch_id": id,
+	}, nil
+}
+
+// submitAsync submits the transactions in one batch and watches it until it's final, see SubmitTxAsync()
+func submitAsync(txs []*tpr.Transaction, done func(status *BatchStatus)) (string, error) {
+	id, err := backendWriter.SubmitBatch(txs)
+	if err != nil {
+		return "", err
+	}
+
+	go watchBatch(id, done)
+
+	return id, nil
+}
+
+// watchBatch waits until the batch with id is final and tells done and the listeners
+func watchBatch(id string, done func(status *BatchStatus)) {
+	status, err := backendWriter.Status(id, true)
+	if err != nil || !contains(finalStatuses, status.Status) {
+		// we gave up waiting
+		fmt.Printf("status of batch %s unknown: %v\n", id, err)
+		status = &BatchStatus{ID: id, Status: "UNKNOWN", InvalidTransactions: make([]InvalidTransaction, 0)}
+	}
+	invalidateReads()
+
+	if done != nil {
+		done(status)
+	}
+
+	batchListenersMu.Lock()
+	listeners := append([]BatchListener{}, batchListeners...)
+	batchListenersMu.Unlock()
+	for _, l := range listeners {
+		l(status)
+	}
+}
+
+// Handle the status of a batch. INVALID batches come with the message of the transaction processor that rejected them, e.g., the message of the error of Apply()
+func (*PayloadBatchStatus) Handle(pl []byte, state StateReader) (map[string]interface{}, error) {
+	var p PayloadBatchStatus
+	err := c.DecodePayload(pl, &p)
+	if err != nil {
+		return nil, validationError("malformed batch status payload", err)
+	}
+	if p.BatchID == "" {
+		return nil, validationError("missing batch id", nil)
+	}
+
+	status, err := backendWriter.Status(p.BatchID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := map[string]interface{}{
+		"batch_id": status.ID,
+		"status":   status.Status,
+	}
+	if len(status.InvalidTransactions) != 0 {
+		ret["transaction_id"] = status.InvalidTransactions[0].ID
+		ret["message"] = status.InvalidTransactions[0].Message
+	}
+
+	return ret, nil
+}
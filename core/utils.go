@@ -21,15 +21,6 @@ const (
 	NamespaceLength = 6
 )
 
-// VerifyPermission verify that initiator is permitted to transact in this transaction family
-func VerifyPermission(familyName string, pubKey []byte) bool {
-	// TODO implement this: should query state for permission
-	// TODO logic to submit transaction to set state should be in banking/sysadmin
-	// Note that we set permissions by family name NOT payload, why? It's perfectly logical to expect someone permissioned to set a rule, e.g., to also be permissioned to delete it
-
-	return true // TODO// TODO// TODO// TODO// TODO// TODO// TODO
-}
-
 // VerifySignature signature is that of pl using pubKey
 func VerifySignature(pl, signature, pubKey []byte) bool {
 	sgnContext := sgn.CreateContext(EncryptionAlgoName)
@@ -39,14 +30,31 @@ func VerifySignature(pl, signature, pubKey []byte) bool {
 }
 
 // CreateTransaction build a sawtooth transaction
-func CreateTransaction(pl *c.SignedPayload, familyName string, inputs []string, outputs []string, dependencies []string) *tpr.Transaction {
+func CreateTransaction(pl *c.SignedPayload, familyName string, inputs []string, outputs []string, dependencies []string) (*tpr.Transaction, error) {
+	if familyName != FamilyName(pl.SourceAccount, pl.Type) {
+		return nil, validationError("payload of type "+pl.Type+" on account "+pl.SourceAccount+" does not belong to transaction family "+familyName, nil)
+	}
+
+	if !contains(FamilyVersions, VersionOf(pl)) {
+		return nil, validationError("unsupported version "+pl.Version, nil)
+	}
+
+	// the transaction processor checks the signer too, so it needs to read its role and keys
+	in := append([]string{}, inputs...)
+	for _, a := range authInputs(pl) {
+		if !contains(in, a) {
+			in = append(in, a)
+		}
+	}
+	inputs = in
+
 	nonce := createNonce()
-	bankPrivateKey, bankPubKey := getBankKeys()
+	_, bankPubKey := getBankKeys()
 
 	// json marshaling to get []byte which is needed in Transaction
 	payloadBytes, err := json.Marshal(*pl)
 	if err != nil {
-		panic(err)
+		return nil, internalError("cannot encode signed payload", err)
 	}
 
 	payloadSha512 := HexdigestB(payloadBytes)
@@ -55,7 +63,7 @@ func CreateTransaction(pl *c.SignedPayload, familyName string, inputs []string,
 		BatcherPublicKey: bankPubKey.AsHex(),
 		Dependencies:     dependencies,
 		FamilyName:       familyName,
-		FamilyVersion:    FamilyVersion,
+		FamilyVersion:    VersionOf(pl),
 		Inputs:           inputs,
 		Nonce:            nonce,
 		Outputs:          outputs,
@@ -63,11 +71,17 @@ func CreateTransaction(pl *c.SignedPayload, familyName string, inputs []string,
 		SignerPublicKey:  bankPubKey.AsHex(),
 	}
 
-	headerBytes, err := proto.Marshal(&header)
+	return signTransaction(&header, payloadBytes)
+}
+
+// signTransaction the bank signs the header of every transaction. the signature is the id of the transaction
+func signTransaction(header *tpr.TransactionHeader, payloadBytes []byte) (*tpr.Transaction, error) {
+	headerBytes, err := proto.Marshal(header)
 	if err != nil {
-		panic(err)
+		return nil, internalError("cannot encode transaction header", err)
 	}
 
+	bankPrivateKey, _ := getBankKeys()
 	signer := c.GetSigner(bankPrivateKey)
 	signedHeaderBytes := signer.Sign(headerBytes)
 	signature := hex.EncodeToString(signedHeaderBytes)
@@ -78,12 +92,23 @@ func CreateTransaction(pl *c.SignedPayload, familyName string, inputs []string,
 		Payload:         payloadBytes,
 	}
 
-	return &tx
+	return &tx, nil
+}
+
+// txHeader decodes the header of tx
+func txHeader(tx *tpr.Transaction) (*tpr.TransactionHeader, error) {
+	var header tpr.TransactionHeader
+	err := proto.Unmarshal(tx.Header, &header)
+	if err != nil {
+		return nil, validationError("malformed transaction header", err)
+	}
+
+	return &header, nil
 }
 
 // CheckLength the length required by sawtooth
 func CheckLength(address string) string {
-	// Note: arguably this is only needed in testing. once tests pass, no need to check in production. How to make it only used in testing?
+	// Note: arguably this is only needed in testing. once tests pass, no need to check in production. How to make it only used in testing? Note: addresses are computed from hashes so a wrong length is a bug, hence the panic
 	if len(address) != AddressLength {
 		panic("wrong address length!")
 	}
@@ -120,8 +145,14 @@ func createNonce() string {
 	return ret
 }
 
+// the key files of the bank. variables so that unit tests can sign with keys of their own
+var (
+	bankKeysFile   = c.BatchSignerKeysFile
+	bankPubKeyFile = c.BatchSignerPubKeyFile
+)
+
 func getBankKeys() (bankPrivateKey sgn.PrivateKey, bankPubKey sgn.PublicKey) {
-	bankPrivateKey, bankPubKey = c.GetKeysFromFiles(c.BatchSignerKeysFile)
+	bankPrivateKey, bankPubKey = c.GetKeysFromFiles(bankKeysFile)
 
 	return
 
@@ -136,28 +167,32 @@ func GetBankAuthTools() (bankPubKey sgn.PublicKey, signer *sgn.Signer) {
 	return
 }
 
-func createBatchList(tx *tpr.Transaction) *bpr.BatchList {
+// createBatchList one batch of txs: the validator commits all of them, in order, or none
+func createBatchList(txs ...*tpr.Transaction) (*bpr.BatchList, error) {
 	// bank signs all batches in our model
 	signerKey, signer := GetBankAuthTools()
 
-	txSignature := tx.HeaderSignature
+	ids := make([]string, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.HeaderSignature
+	}
 	header := bpr.BatchHeader{
 		SignerPublicKey: signerKey.AsHex(),
-		TransactionIds:  []string{txSignature},
+		TransactionIds:  ids,
 	}
 
 	headerBytes, err := proto.Marshal(&header)
 	if err != nil {
-		panic(err)
+		return nil, internalError("cannot encode batch header", err)
 	}
 
 	signature := hex.EncodeToString(signer.Sign(headerBytes))
 	batch := bpr.Batch{
 		Header:          headerBytes,
 		HeaderSignature: signature,
-		Transactions:    []*tpr.Transaction{tx},
+		Transactions:    txs,
 	}
 
 	ret := &bpr.BatchList{Batches: []*bpr.Batch{&batch}}
-	return ret
+	return ret, nil
 }
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+
+	c "../common"
+	pb "../protos"
+	"google.golang.org/protobuf/proto"
+)
+
+// StateVersion of the data core writes in the state. 0.1 wrote bare json, e.g., the []string of pub keys, or raw strings, e.g., group names. 0.2 wrapped json in a json stateRecord. From 0.3 on the data is a protobuf message wrapped in a StateRecord, see ../protos/state.proto, so its format can change again without breaking what's already in the state
+const StateVersion = "0.3"
+
+// versions of data that isn't wrapped in a protobuf StateRecord
+const (
+	legacyStateVersion = "0.1"
+	jsonStateVersion   = "0.2"
+)
+
+// kinds of data in the state
+const (
+	stateRule               = "rule"
+	stateGroup              = "group"
+	statePubKeys            = "pub_keys"
+	stateRecipientAccount   = "recipient_account"
+	statePendingTxSigs      = "pending_tx_sigs"
+	statePendingTxInitiator = "pending_tx_initiator"
+	stateRoles              = "roles"
+	stateSpendLedger        = "spend_ledger"
+	stateAccountPolicy      = "account_policy"
+	stateHolidayCalendar    = "holiday_calendar"
+)
+
+// stateRecord is the json record of 0.2
+type stateRecord struct {
+	Version string          `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// stateMigration upcasts data of a kind from one version to a later one
+type stateMigration struct {
+	To     string
+	Upcast func(data []byte) ([]byte, error)
+}
+
+// kind => from version => migration
+var stateMigrations = make(map[string]map[string]stateMigration)
+
+func init() {
+	// 0.1 pub keys are the same []string, not wrapped
+	registerStateMigration(statePubKeys, legacyStateVersion, jsonStateVersion, func(data []byte) ([]byte, error) {
+		return data, nil
+	})
+	registerStateMigration(statePubKeys, jsonStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		var keys []string
+		err := json.Unmarshal(data, &keys)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.MarshalProto(&pb.PubKeys{Keys: keys})
+	})
+	// 0.1 PendingTxSigsInfo had no json tags
+	registerStateMigration(statePendingTxSigs, legacyStateVersion, jsonStateVersion, func(data []byte) ([]byte, error) {
+		var v struct {
+			AuthorisedSigs  []map[string]bool
+			RequiredMinSigs []int
+		}
+		err := json.Unmarshal(data, &v)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(PendingTxSigsInfo{AuthorisedSigs: v.AuthorisedSigs, RequiredMinSigs: v.RequiredMinSigs})
+	})
+	registerStateMigration(statePendingTxSigs, jsonStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		var sigsInfo PendingTxSigsInfo
+		err := json.Unmarshal(data, &sigsInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.MarshalProto(sigsInfoToProto(&sigsInfo))
+	})
+	// the kinds below were never wrapped in json: 0.1 goes straight to 0.3
+	registerStateMigration(stateRule, legacyStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		var r ARule
+		err := json.Unmarshal(data, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.MarshalProto(&pb.Rule{Rule: r.Rule, RuleHash: r.RuleHash})
+	})
+	registerStateMigration(stateGroup, legacyStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		return c.MarshalProto(&pb.Group{Name: string(data)})
+	})
+	registerStateMigration(stateRecipientAccount, legacyStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		return c.MarshalProto(&pb.RecipientAccount{DestAccount: string(data)})
+	})
+	registerStateMigration(statePendingTxInitiator, legacyStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		return c.MarshalProto(&pb.PendingTxInitiator{Initiator: string(data)})
+	})
+	registerStateMigration(stateRoles, legacyStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		var roles []string
+		err := json.Unmarshal(data, &roles)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.MarshalProto(&pb.Roles{Roles: roles})
+	})
+	registerStateMigration(stateSpendLedger, legacyStateVersion, StateVersion, func(data []byte) ([]byte, error) {
+		var entries []SpendEntry
+		err := json.Unmarshal(data, &entries)
+		if err != nil {
+			return nil, err
+		}
+
+		ledger := &pb.SpendLedger{}
+		for _, e := range entries {
+			ledger.Entries = append(ledger.Entries, &pb.SpendEntry{Time: e.Time, Amount: e.Amount})
+		}
+		return c.MarshalProto(ledger)
+	})
+}
+
+// registerStateMigration adds the migration of kind from version from to version to. Migrations chain: 0.1 => 0.2 => ... => StateVersion
+func registerStateMigration(kind, from, to string, upcast func(data []byte) ([]byte, error)) {
+	if _, ok := stateMigrations[kind]; !ok {
+		stateMigrations[kind] = make(map[string]stateMigration)
+	}
+	if _, ok := stateMigrations[kind][from]; ok {
+		panic("migration of " + kind + " from " + from + " registered twice")
+	}
+
+	stateMigrations[kind][from] = stateMigration{To: to, Upcast: upcast}
+}
+
+// encodeState m in the current format. Note: deterministic, every validator must write the same bytes
+func encodeState(m proto.Message) ([]byte, error) {
+	data, err := c.MarshalProto(m)
+	if err != nil {
+		return nil, internalError("cannot encode state", err)
+	}
+
+	ret, err := c.MarshalProto(&pb.StateRecord{Version: StateVersion, Data: data})
+	if err != nil {
+		return nil, internalError("cannot encode state", err)
+	}
+
+	return ret, nil
+}
+
+// decodeState data of kind, in whatever version it was written, into m. Note: data is upcast when read, never rewritten in place. it's written in the current format the next time it changes
+func decodeState(kind string, data []byte, m proto.Message) error {
+	version, data := unwrapState(data)
+	for version != StateVersion {
+		migration, ok := stateMigrations[kind][version]
+		if !ok {
+			return stateError("no migration of "+kind+" from version "+version, nil)
+		}
+
+		var err error
+		data, err = migration.Upcast(data)
+		if err != nil {
+			return stateError("cannot migrate "+kind+" from version "+version, err)
+		}
+		version = migration.To
+	}
+
+	err := proto.Unmarshal(data, m)
+	if err != nil {
+		return stateError("malformed "+kind+" in the state", err)
+	}
+
+	return nil
+}
+
+// unwrapState returns the version of data and the data without its record. a protobuf StateRecord starts with its version field, i.e., '\n', which no json and no raw string of 0.1 starts with
+func unwrapState(data []byte) (string, []byte) {
+	if len(data) > 0 && data[0] == '\n' {
+		var r pb.StateRecord
+		if proto.Unmarshal(data, &r) == nil && r.Version != "" {
+			return r.Version, r.Data
+		}
+	}
+
+	var r stateRecord
+	if json.Unmarshal(data, &r) == nil && r.Version != "" {
+		return r.Version, r.Data
+	}
+
+	return legacyStateVersion, data
+}
+
+// VersionOf returns the family version of the transaction pl ends up in
+func VersionOf(pl *c.SignedPayload) string {
+	if pl.Version == "" {
+		return c.LegacyPayloadVersion
+	}
+
+	return pl.Version
+}
+
+// CheckPayload checks that pl has a version the transaction processor handles, is encoded as its version says, that its payload matches the definition of its type and is on the account pl is on. the lambda and the transaction processor both call it before anything else
+func CheckPayload(pl *c.SignedPayload) error {
+	if !contains(FamilyVersions, VersionOf(pl)) {
+		return validationError("unsupported version "+pl.Version, nil)
+	}
+	if c.IsJSONPayload(pl.Payload) != (VersionOf(pl) != c.PayloadVersion) {
+		return validationError("payload encoding does not match version "+VersionOf(pl), nil)
+	}
+
+	// payloads only the bank creates, e.g., set_pending_tx, have no schema
+	if _, ok := c.PayloadSchema(pl.Type); ok {
+		err := c.ValidatePayload(pl.Type, pl.Payload)
+		if err != nil {
+			return validationError("payload does not match the schema of "+pl.Type, err)
+		}
+	}
+
+	return checkPayloadAccount(pl)
+}
+
+// checkPayloadAccount the payload pl wraps must be on the account pl is on: family names, initiator keys and permissions are checked against pl.SourceAccount, while Apply() acts on the account in the payload
+func checkPayloadAccount(pl *c.SignedPayload) error {
+	pc, ok := LookupPayload(pl.Type)
+	if !ok {
+		return validationError("unknown payload type "+pl.Type, nil)
+	}
+	var p interface{}
+	if pc.Applier != nil {
+		p = pc.Applier()
+	} else {
+		p = pc.Querier()
+	}
+	err := c.DecodePayload(pl.Payload, p)
+	if err != nil {
+		return validationError("malformed "+pl.Type+" payload", err)
+	}
+
+	a := reflect.Indirect(reflect.ValueOf(p)).FieldByName("SourceAccount")
+	if !a.IsValid() || a.String() != pl.SourceAccount {
+		return authorizationError(pl.Type + " payload is not on account " + pl.SourceAccount + " it is signed for")
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	c "../common"
+	pb "../protos"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDecodeState(t *testing.T) {
+	current, err := encodeState(&pb.PubKeys{Keys: []string{"02ab"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		kind string
+		data string
+		m    proto.Message
+		want proto.Message
+	}{
+		{"pub keys 0.1", statePubKeys, `["02ab","03cd"]`, &pb.PubKeys{}, &pb.PubKeys{Keys: []string{"02ab", "03cd"}}},
+		{"pub keys 0.2", statePubKeys, `{"version":"0.2","data":["02ab"]}`, &pb.PubKeys{}, &pb.PubKeys{Keys: []string{"02ab"}}},
+		{"pub keys 0.3", statePubKeys, string(current), &pb.PubKeys{}, &pb.PubKeys{Keys: []string{"02ab"}}},
+		{"pending tx sigs 0.1", statePendingTxSigs, `{"AuthorisedSigs":[{"alice":true,"bob":false}],"RequiredMinSigs":[1]}`, &pb.PendingTxSigsInfo{},
+			&pb.PendingTxSigsInfo{AuthorisedSigs: []*pb.Signers{{Signed: map[string]bool{"alice": true, "bob": false}}}, RequiredMinSigs: []int32{1}}},
+		{"pending tx sigs 0.2", statePendingTxSigs, `{"version":"0.2","data":{"authorised_sigs":[{"alice":false}],"required_min_sigs":[2]}}`, &pb.PendingTxSigsInfo{},
+			&pb.PendingTxSigsInfo{AuthorisedSigs: []*pb.Signers{{Signed: map[string]bool{"alice": false}}}, RequiredMinSigs: []int32{2}}},
+		{"rule 0.1", stateRule, `{"rule":"Amount > 100 ? 'deny' : 'nil'","rulehash":"abc"}`, &pb.Rule{}, &pb.Rule{Rule: "Amount > 100 ? 'deny' : 'nil'", RuleHash: "abc"}},
+		{"group 0.1", stateGroup, `finance`, &pb.Group{}, &pb.Group{Name: "finance"}},
+		{"recipient account 0.1", stateRecipientAccount, `12345678`, &pb.RecipientAccount{}, &pb.RecipientAccount{DestAccount: "12345678"}},
+		{"pending tx initiator 0.1", statePendingTxInitiator, `CD34YG4`, &pb.PendingTxInitiator{}, &pb.PendingTxInitiator{Initiator: "CD34YG4"}},
+		{"roles 0.1", stateRoles, `["signer"]`, &pb.Roles{}, &pb.Roles{Roles: []string{RoleSigner}}},
+		{"spend ledger 0.1", stateSpendLedger, `[{"time":1,"amount":2.5}]`, &pb.SpendLedger{}, &pb.SpendLedger{Entries: []*pb.SpendEntry{{Time: 1, Amount: 2.5}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decodeState(tt.kind, []byte(tt.data), tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(tt.m, tt.want) {
+				t.Errorf("decodeState() = %v, want %v", tt.m, tt.want)
+			}
+		})
+	}
+
+	// a version without a migration, e.g., written by a later release, is a state error rather than garbage
+	err = decodeState(stateGroup, []byte(`{"version":"9.9","data":"finance"}`), &pb.Group{})
+	if KindOf(err) != KindState {
+		t.Errorf("decodeState() of an unknown version = %v, want a state error", err)
+	}
+	err = decodeState(statePubKeys, []byte(`not json`), &pb.PubKeys{})
+	if KindOf(err) != KindState {
+		t.Errorf("decodeState() of malformed 0.1 pub keys = %v, want a state error", err)
+	}
+}
+
+// the rules, groups and pub keys 0.1 wrote still decide queries and authorise requests
+func TestLegacyState(t *testing.T) {
+	s := NewMemoryState()
+	root := initiatorRootStateAddress(testAccount)
+	rule := "Amount > 100 ? 'deny' : 'nil'"
+	legacyRule, _ := json.Marshal(ARule{Rule: rule, RuleHash: getRuleHash(initiatorRule(root, "finance", rule))})
+	legacyKeys, _ := json.Marshal([]string{"02ab"})
+	s.SetState(map[string][]byte{
+		initiatorRule(root, "finance", rule):       legacyRule,
+		initiatorGroup(root, "CD34YG4", "finance"): []byte("finance"),
+		initiatorPubKeys(root, "CD34YG4"):          legacyKeys,
+	})
+
+	m := map[string]interface{}{"Amount": 150.0, "SourceAccount": testAccount}
+	bindClock(m, at("2026-03-01T12:00:00Z"))
+	ret, err := queryRules(testAccount, "CD34YG4", m, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret["action"] != "deny" {
+		t.Errorf("queryRules() with 0.1 rules = %v, want deny", ret)
+	}
+
+	pl, err := c.EncodePayload(c.PayloadQueryAuth{SourceAccount: testAccount, Initiator: "CD34YG4", Amount: 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]bool{"02ab": true, "03cd": false} {
+		pubKey, _ := hex.DecodeString(key)
+		ok, err := VerifyInitiatorKey(&c.SignedPayload{Version: c.PayloadVersion, Type: "query_auth", SourceAccount: testAccount, SignerPubKey: pubKey, Payload: pl}, s)
+		if err != nil || ok != want {
+			t.Errorf("VerifyInitiatorKey() of %s against 0.1 pub keys = %v, %v, want %v", key, ok, err, want)
+		}
+	}
+}
+
+func TestCheckPayloadVersions(t *testing.T) {
+	pb3, err := c.EncodePayload(c.PayloadListRecipient{SourceAccount: testAccount, Recipient: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, _ := json.Marshal(c.PayloadListRecipient{SourceAccount: testAccount, Recipient: "bob"})
+
+	tests := []struct {
+		name    string
+		version string
+		payload []byte
+		ok      bool
+	}{
+		{"0.3 protobuf", c.PayloadVersion, pb3, true},
+		{"0.2 json", c.JSONPayloadVersion, js, true},
+		{"0.1 json", c.LegacyPayloadVersion, js, true},
+		{"no version is 0.1", "", js, true},
+		{"0.3 json", c.PayloadVersion, js, false},
+		{"0.2 protobuf", c.JSONPayloadVersion, pb3, false},
+		{"unknown version", "9.9", js, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPayload(&c.SignedPayload{Version: tt.version, Type: "list_recipient", SourceAccount: testAccount, Payload: tt.payload})
+			if (err == nil) != tt.ok {
+				t.Errorf("CheckPayload() = %v, want ok %v", err, tt.ok)
+			}
+			if err != nil && KindOf(err) != KindValidation {
+				t.Errorf("CheckPayload() = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -6,9 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"reflect"
-
-	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"strings"
+	"time"
 
 	c "../common"
 	"../core"
@@ -17,16 +16,61 @@ import (
 )
 
 func main() {
+	cbs, err := core.NewFileCoreBanking(c.CoreBankingFile)
+	if err != nil {
+		panic(err)
+	}
+	core.SetCoreBanking(cbs)
+	if c.StatusWebhook != "" {
+		core.AddBatchListener(core.Webhook(c.StatusWebhook))
+	}
+	// queries on busy accounts read from a local index of their state instead of making round trips to the rest api
+	if c.IndexedAccounts != "" {
+		rest := &core.RestClient{URL: c.RestAPI, PageLimit: c.RestAPIPageLimit}
+		indexer := core.NewIndexer(rest, strings.Split(c.IndexedAccounts, ","))
+		go func() {
+			for {
+				err := indexer.Run(c.ValidatorEndpoint)
+				fmt.Printf("indexer stopped: %v\n", err)
+				time.Sleep(5 * time.Second)
+			}
+		}()
+		core.SetStateBackend(indexer, rest)
+	}
+
 	// handler for my rest api
 	// request resulting in blockchain transaction launches transaction processor and records name in a list. if new transaction on same family_name and family_version comes no new transaction processor is launched. when response sent back (see lambdahandler above) send shutdown signal to transaction processor (don't know how to send signal yet.)
 
 	http.HandleFunc("/", func(w http.ResponseWriter, request *http.Request) {
-		var p c.SignedPayload
+		// one bad request must not take the server down. panics left in the code, e.g., missing key files, are internal errors
+		defer func() {
+			if r := recover(); r != nil {
+				writeError(w, fmt.Errorf("%v", r))
+			}
+		}()
+
 		body := new(bytes.Buffer)
 		body.ReadFrom(request.Body)
+		// a json array of signed payloads is a batch, see core.SubmitBatch()
+		if b := bytes.TrimLeft(body.Bytes(), " \t\r\n"); len(b) > 0 && b[0] == '[' {
+			handleBatch(w, b)
+			return
+		}
+
+		var p c.SignedPayload
 		err := json.Unmarshal(body.Bytes(), &p)
 		if err != nil {
-			panic(err)
+			writeError(w, &core.Error{Kind: core.KindValidation, Msg: "malformed signed payload", Err: err})
+			return
+		}
+		if _, ok := core.LookupPayload(p.Type); !ok {
+			writeError(w, &core.Error{Kind: core.KindValidation, Msg: "unknown request type " + p.Type})
+			return
+		}
+		err = core.CheckPayload(&p)
+		if err != nil {
+			writeError(w, err)
+			return
 		}
 
 		t := p.Type
@@ -36,23 +80,27 @@ func main() {
 
 		// launch transaction processor on separate thread because it blocks and polls for messages from validator
 		go tprocessor.Launch(fn)
+		// TODO TODO the request to shut down is based on the crucial assumption that lambdaHandler() blocks until the query completes. state changing requests don't wait for their transaction, lambdaHandler() holds the processor until the batch is final. deferred so it happens on errors too
+		defer tprocessor.ShutDown(fn)
 
-		resp := lambdaHandler(&p)
+		resp, err := lambdaHandler(&p)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
 
 		b, err := json.Marshal(resp)
 		if err != nil {
-			panic(err)
+			writeError(w, err)
+			return
 		}
 
-		// TODO TODO the request to shut down next is based on the crucial assumption that lambdaHandler() blocks until the query or the transaction completes -- which I believe is very much the case
-		tprocessor.ShutDown(fn)
-
 		// TODO needs more work. i'm displaying a json of a map.
 		io.WriteString(w, string(b))
 	})
 
 	////////log.Printf("About to listen on 8443. Go to https://127.0.0.1:8443/")
-	err := http.ListenAndServe(":8443", nil) // TODO upgrade to TLS after figuring out certificates
+	err = http.ListenAndServe(":8443", nil) // TODO upgrade to TLS after figuring out certificates
 	// err := http.ListenAndServeTLS(":8443", "cert.pem", "key.pem", nil)
 	////////log.Fatal(err)
 	if err != nil {
@@ -61,40 +109,98 @@ func main() {
 
 }
 
+// handleBatch submits the signed payloads in body in one batch and sends back its id and the transaction of each request. like other state changing requests we don't wait for the batch to be committed
+func handleBatch(w http.ResponseWriter, body []byte) {
+	var ps []*c.SignedPayload
+	err := json.Unmarshal(body, &ps)
+	if err != nil {
+		writeError(w, &core.Error{Kind: core.KindValidation, Msg: "malformed batch of signed payloads", Err: err})
+		return
+	}
+
+	// one transaction processor per family in the batch, see above, held until the batch is final
+	fns := make(map[string]bool)
+	for _, p := range ps {
+		fns[core.FamilyName(p.SourceAccount, p.Type)] = true
+	}
+	for fn := range fns {
+		go tprocessor.Launch(fn)
+	}
+	shutDown := func(*core.BatchStatus) {
+		for fn := range fns {
+			tprocessor.ShutDown(fn)
+		}
+	}
+
+	id, statuses, err := core.SubmitBatch(ps, shutDown)
+	if err != nil {
+		shutDown(nil)
+		writeError(w, err)
+		return
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{"batch_id": id, "transactions": statuses})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// http status codes for the kinds of errors core returns
+var errorKindToStatus = map[core.ErrorKind]int{
+	core.KindValidation:    http.StatusBadRequest,
+	core.KindAuthorization: http.StatusForbidden,
+	core.KindNotFound:      http.StatusNotFound,
+	core.KindState:         http.StatusBadGateway, // the rest api, validator or core banking system behind us failed
+	core.KindInternal:      http.StatusInternalServerError,
+}
+
+// writeError sends err back as json with the status code for its kind
+func writeError(w http.ResponseWriter, err error) {
+	b, _ := json.Marshal(c.ResponseGateway{Error: err.Error()})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(errorKindToStatus[core.KindOf(err)])
+	w.Write(b)
+}
+
 // lambdaHandler is the handler we pass to AWS Lambda. If I've done this right, then new types of transaction can be added without this file being touched. Note: we're relying on the JSON struct tags produced by protoc
-func lambdaHandler(p *c.SignedPayload) map[string]string {
+func lambdaHandler(p *c.SignedPayload) (map[string]string, error) {
 
 	var resp map[string]interface{}
-	t := core.PayloadRegistry[p.Type]
-	// Look for Handle() method on payload. Handle() is used for querying the state without changing it. One exception, as of today, 9/6/18, is query_auth which, after querying the state, might submit a transaction to the validator to set a "pending bank transaction" if the bank transaction requires multiple signatures
-	f := reflect.New(t).Elem().MethodByName("Handle")
-	if f.IsValid() {
-		v0 := reflect.ValueOf(p.Payload)
-		ar := f.Call([]reflect.Value{v0})
-		m := ar[0].Interface()
-		var ok bool
-		resp, ok = m.(map[string]interface{})
-		if !ok {
-			panic("payload handler failed")
-		}
-	} else {
-		// We're here therefore the payload changes the state and so a transaction has to be submitted to the validator. We construct the transaction out of the payload we received and submit it through the sawtooth rest API
-		f = reflect.New(t).Elem().MethodByName("WrapInTx")
-		if !f.IsValid() {
-			panic("payload is missing a Handle() or WrapInTx() method")
+	pc, _ := core.LookupPayload(p.Type)
+	// the request reads the state at one block, whatever is committed meanwhile
+	state, err := core.PinState()
+	if err != nil {
+		return nil, err
+	}
+	// Handle() is used for querying the state without changing it. One exception, as of today, 9/6/18, is query_auth which, after querying the state, might submit a transaction to the validator to set a "pending bank transaction" if the bank transaction requires multiple signatures
+	if pc.Querier != nil {
+		// Handle() only sees the payload, not who signed it. so requests that need a role, e.g., query_auth, are checked here. WrapInTx() checks the others
+		err := authorise(p, state)
+		if err != nil {
+			return nil, err
 		}
 
-		// After transaction is submitted, the validator passes the request to our transaction processor, which in turn invokes the Apply() method on the payload. Note: g is not needed here. we're only checking for errors
-		g := reflect.New(t).Elem().MethodByName("Apply")
-		if !g.IsValid() {
-			panic("payload must have Apply() method in addition to WrapInTx() method")
+		resp, err = pc.Querier().Handle(p.Payload, state)
+		if err != nil {
+			return nil, err
 		}
-
-		v0 := reflect.ValueOf(p)
-		ar := f.Call([]reflect.Value{v0})
-		m := ar[0].Interface()
-		tx := m.(*transaction_pb2.Transaction)
-		resp = core.SubmitTx(tx)
+	} else if pc.Wrapper != nil {
+		// We're here therefore the payload changes the state and so a transaction has to be submitted to the validator. We construct the transaction out of the payload we received and submit it through the sawtooth rest API. After transaction is submitted, the validator passes the request to our transaction processor, which in turn invokes the Apply() method on the payload, RegisterPayload() made sure there's one
+		tx, err := pc.Wrapper().WrapInTx(p, state)
+		if err != nil {
+			return nil, err
+		}
+		// we don't wait for the transaction to be committed: the client gets the id of the batch and queries its status with batch_status requests, or gets it from the webhook. the transaction processor must outlive this request though, so we hold it until the batch is final
+		fn := core.FamilyName(p.SourceAccount, p.Type)
+		go tprocessor.Launch(fn)
+		resp, err = core.SubmitTxAsync(tx, func(*core.BatchStatus) { tprocessor.ShutDown(fn) })
+		if err != nil {
+			tprocessor.ShutDown(fn)
+			return nil, err
+		}
+	} else {
+		// e.g., set_pending_tx: only the bank creates those
+		return nil, &core.Error{Kind: core.KindValidation, Msg: p.Type + " requests cannot be submitted"}
 	}
 
 	ret := make(map[string]string, 0)
@@ -102,5 +208,30 @@ func lambdaHandler(p *c.SignedPayload) map[string]string {
 		ret[k] = fmt.Sprintf("%v", v)
 	}
 
-	return ret
+	return ret, nil
+}
+
+// authorise checks the signature, the initiator key and the role of the signer of p in state
+func authorise(p *c.SignedPayload, state core.StateReader) error {
+	if !core.VerifySignature(p.Payload, p.Signature, p.SignerPubKey) {
+		return &core.Error{Kind: core.KindAuthorization, Msg: "invalid signature for " + p.Type + " request"}
+	}
+
+	ok, err := core.VerifyInitiatorKey(p, state)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &core.Error{Kind: core.KindAuthorization, Msg: "key of " + p.Type + " request is not registered for its initiator"}
+	}
+
+	ok, err = core.VerifyPermission(p.SourceAccount, p.Type, p.SignerPubKey, state)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &core.Error{Kind: core.KindAuthorization, Msg: "signer of " + p.Type + " request is not authorised"}
+	}
+
+	return nil
 }
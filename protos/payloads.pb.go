@@ -0,0 +1,2508 @@
+// Payloads of version 0.3 and later, see common.PayloadVersion. One message per payload struct in common/payloads.go (and core for the payloads only the bank creates) with the same fields: common.EncodePayload() and common.DecodePayload() convert between the two by field name, so a field added to a struct must be added here too
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: payloads.proto
+
+package protos
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PayloadSetInitiatorRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Rule          string `protobuf:"bytes,3,opt,name=rule,proto3" json:"rule,omitempty"`
+	ValidFrom     int64  `protobuf:"varint,4,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidUntil    int64  `protobuf:"varint,5,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
+	Priority      int32  `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	Override      string `protobuf:"bytes,7,opt,name=override,proto3" json:"override,omitempty"`
+}
+
+func (x *PayloadSetInitiatorRule) Reset() {
+	*x = PayloadSetInitiatorRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadSetInitiatorRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadSetInitiatorRule) ProtoMessage() {}
+
+func (x *PayloadSetInitiatorRule) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadSetInitiatorRule.ProtoReflect.Descriptor instead.
+func (*PayloadSetInitiatorRule) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PayloadSetInitiatorRule) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadSetInitiatorRule) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadSetInitiatorRule) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *PayloadSetInitiatorRule) GetValidFrom() int64 {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return 0
+}
+
+func (x *PayloadSetInitiatorRule) GetValidUntil() int64 {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return 0
+}
+
+func (x *PayloadSetInitiatorRule) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *PayloadSetInitiatorRule) GetOverride() string {
+	if x != nil {
+		return x.Override
+	}
+	return ""
+}
+
+type PayloadDeleteInitiatorRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	RuleHash      string `protobuf:"bytes,3,opt,name=rule_hash,json=ruleHash,proto3" json:"rule_hash,omitempty"`
+}
+
+func (x *PayloadDeleteInitiatorRule) Reset() {
+	*x = PayloadDeleteInitiatorRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadDeleteInitiatorRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadDeleteInitiatorRule) ProtoMessage() {}
+
+func (x *PayloadDeleteInitiatorRule) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadDeleteInitiatorRule.ProtoReflect.Descriptor instead.
+func (*PayloadDeleteInitiatorRule) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PayloadDeleteInitiatorRule) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadDeleteInitiatorRule) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadDeleteInitiatorRule) GetRuleHash() string {
+	if x != nil {
+		return x.RuleHash
+	}
+	return ""
+}
+
+type PayloadListInitiatorRules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+}
+
+func (x *PayloadListInitiatorRules) Reset() {
+	*x = PayloadListInitiatorRules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadListInitiatorRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadListInitiatorRules) ProtoMessage() {}
+
+func (x *PayloadListInitiatorRules) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadListInitiatorRules.ProtoReflect.Descriptor instead.
+func (*PayloadListInitiatorRules) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PayloadListInitiatorRules) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadListInitiatorRules) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+type PayloadListInitiatorGroups struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+}
+
+func (x *PayloadListInitiatorGroups) Reset() {
+	*x = PayloadListInitiatorGroups{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadListInitiatorGroups) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadListInitiatorGroups) ProtoMessage() {}
+
+func (x *PayloadListInitiatorGroups) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadListInitiatorGroups.ProtoReflect.Descriptor instead.
+func (*PayloadListInitiatorGroups) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PayloadListInitiatorGroups) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadListInitiatorGroups) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+type PayloadListInitiatorPubKeys struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+}
+
+func (x *PayloadListInitiatorPubKeys) Reset() {
+	*x = PayloadListInitiatorPubKeys{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadListInitiatorPubKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadListInitiatorPubKeys) ProtoMessage() {}
+
+func (x *PayloadListInitiatorPubKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadListInitiatorPubKeys.ProtoReflect.Descriptor instead.
+func (*PayloadListInitiatorPubKeys) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PayloadListInitiatorPubKeys) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadListInitiatorPubKeys) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+type PayloadAddInitiatorToGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Group         string `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+}
+
+func (x *PayloadAddInitiatorToGroup) Reset() {
+	*x = PayloadAddInitiatorToGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadAddInitiatorToGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadAddInitiatorToGroup) ProtoMessage() {}
+
+func (x *PayloadAddInitiatorToGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadAddInitiatorToGroup.ProtoReflect.Descriptor instead.
+func (*PayloadAddInitiatorToGroup) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PayloadAddInitiatorToGroup) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadAddInitiatorToGroup) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadAddInitiatorToGroup) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+type PayloadRemoveInitiatorFromGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Group         string `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+}
+
+func (x *PayloadRemoveInitiatorFromGroup) Reset() {
+	*x = PayloadRemoveInitiatorFromGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadRemoveInitiatorFromGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadRemoveInitiatorFromGroup) ProtoMessage() {}
+
+func (x *PayloadRemoveInitiatorFromGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadRemoveInitiatorFromGroup.ProtoReflect.Descriptor instead.
+func (*PayloadRemoveInitiatorFromGroup) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PayloadRemoveInitiatorFromGroup) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadRemoveInitiatorFromGroup) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadRemoveInitiatorFromGroup) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+type PayloadSetInitiatorPubKeys struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string   `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string   `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	PubKeys       []string `protobuf:"bytes,3,rep,name=pub_keys,json=pubKeys,proto3" json:"pub_keys,omitempty"`
+}
+
+func (x *PayloadSetInitiatorPubKeys) Reset() {
+	*x = PayloadSetInitiatorPubKeys{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadSetInitiatorPubKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadSetInitiatorPubKeys) ProtoMessage() {}
+
+func (x *PayloadSetInitiatorPubKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadSetInitiatorPubKeys.ProtoReflect.Descriptor instead.
+func (*PayloadSetInitiatorPubKeys) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PayloadSetInitiatorPubKeys) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadSetInitiatorPubKeys) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadSetInitiatorPubKeys) GetPubKeys() []string {
+	if x != nil {
+		return x.PubKeys
+	}
+	return nil
+}
+
+type PayloadDeleteInitiatorPubKeys struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string   `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string   `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	PubKeys       []string `protobuf:"bytes,3,rep,name=pub_keys,json=pubKeys,proto3" json:"pub_keys,omitempty"`
+}
+
+func (x *PayloadDeleteInitiatorPubKeys) Reset() {
+	*x = PayloadDeleteInitiatorPubKeys{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadDeleteInitiatorPubKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadDeleteInitiatorPubKeys) ProtoMessage() {}
+
+func (x *PayloadDeleteInitiatorPubKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadDeleteInitiatorPubKeys.ProtoReflect.Descriptor instead.
+func (*PayloadDeleteInitiatorPubKeys) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PayloadDeleteInitiatorPubKeys) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadDeleteInitiatorPubKeys) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadDeleteInitiatorPubKeys) GetPubKeys() []string {
+	if x != nil {
+		return x.PubKeys
+	}
+	return nil
+}
+
+type PayloadQueryAuth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string  `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string  `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Recipient     string  `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Action        string  `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Amount        float64 `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	DestAccount   string  `protobuf:"bytes,6,opt,name=dest_account,json=destAccount,proto3" json:"dest_account,omitempty"`
+}
+
+func (x *PayloadQueryAuth) Reset() {
+	*x = PayloadQueryAuth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadQueryAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadQueryAuth) ProtoMessage() {}
+
+func (x *PayloadQueryAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadQueryAuth.ProtoReflect.Descriptor instead.
+func (*PayloadQueryAuth) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PayloadQueryAuth) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadQueryAuth) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadQueryAuth) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *PayloadQueryAuth) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *PayloadQueryAuth) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PayloadQueryAuth) GetDestAccount() string {
+	if x != nil {
+		return x.DestAccount
+	}
+	return ""
+}
+
+type PayloadSimulateAuth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string              `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string              `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Recipient     string              `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Action        string              `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Amount        float64             `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	DestAccount   string              `protobuf:"bytes,6,opt,name=dest_account,json=destAccount,proto3" json:"dest_account,omitempty"`
+	ExtraRules    []*HypotheticalRule `protobuf:"bytes,7,rep,name=extra_rules,json=extraRules,proto3" json:"extra_rules,omitempty"`
+}
+
+func (x *PayloadSimulateAuth) Reset() {
+	*x = PayloadSimulateAuth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadSimulateAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadSimulateAuth) ProtoMessage() {}
+
+func (x *PayloadSimulateAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadSimulateAuth.ProtoReflect.Descriptor instead.
+func (*PayloadSimulateAuth) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PayloadSimulateAuth) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadSimulateAuth) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadSimulateAuth) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *PayloadSimulateAuth) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *PayloadSimulateAuth) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PayloadSimulateAuth) GetDestAccount() string {
+	if x != nil {
+		return x.DestAccount
+	}
+	return ""
+}
+
+func (x *PayloadSimulateAuth) GetExtraRules() []*HypotheticalRule {
+	if x != nil {
+		return x.ExtraRules
+	}
+	return nil
+}
+
+type HypotheticalRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Initiator string `protobuf:"bytes,1,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Rule      string `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+}
+
+func (x *HypotheticalRule) Reset() {
+	*x = HypotheticalRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HypotheticalRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HypotheticalRule) ProtoMessage() {}
+
+func (x *HypotheticalRule) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HypotheticalRule.ProtoReflect.Descriptor instead.
+func (*HypotheticalRule) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HypotheticalRule) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *HypotheticalRule) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+type PayloadClosePendingTx struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	TransactionId string `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Initiator     string `protobuf:"bytes,3,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	InitiatorKey  []byte `protobuf:"bytes,4,opt,name=initiator_key,json=initiatorKey,proto3" json:"initiator_key,omitempty"`
+}
+
+func (x *PayloadClosePendingTx) Reset() {
+	*x = PayloadClosePendingTx{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadClosePendingTx) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadClosePendingTx) ProtoMessage() {}
+
+func (x *PayloadClosePendingTx) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadClosePendingTx.ProtoReflect.Descriptor instead.
+func (*PayloadClosePendingTx) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PayloadClosePendingTx) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadClosePendingTx) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PayloadClosePendingTx) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadClosePendingTx) GetInitiatorKey() []byte {
+	if x != nil {
+		return x.InitiatorKey
+	}
+	return nil
+}
+
+type PayloadAddSigTx struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	TransactionId string `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Signature     []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	PubKey        []byte `protobuf:"bytes,4,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Initiator     string `protobuf:"bytes,5,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Time          int64  `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *PayloadAddSigTx) Reset() {
+	*x = PayloadAddSigTx{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadAddSigTx) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadAddSigTx) ProtoMessage() {}
+
+func (x *PayloadAddSigTx) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadAddSigTx.ProtoReflect.Descriptor instead.
+func (*PayloadAddSigTx) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PayloadAddSigTx) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadAddSigTx) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PayloadAddSigTx) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *PayloadAddSigTx) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *PayloadAddSigTx) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadAddSigTx) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+type PayloadListPendingTx struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Initiator     string `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+}
+
+func (x *PayloadListPendingTx) Reset() {
+	*x = PayloadListPendingTx{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadListPendingTx) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadListPendingTx) ProtoMessage() {}
+
+func (x *PayloadListPendingTx) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadListPendingTx.ProtoReflect.Descriptor instead.
+func (*PayloadListPendingTx) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PayloadListPendingTx) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadListPendingTx) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+type PayloadSetRecipient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Recipient     string `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	DestAccount   string `protobuf:"bytes,3,opt,name=dest_account,json=destAccount,proto3" json:"dest_account,omitempty"`
+}
+
+func (x *PayloadSetRecipient) Reset() {
+	*x = PayloadSetRecipient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadSetRecipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadSetRecipient) ProtoMessage() {}
+
+func (x *PayloadSetRecipient) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadSetRecipient.ProtoReflect.Descriptor instead.
+func (*PayloadSetRecipient) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PayloadSetRecipient) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadSetRecipient) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *PayloadSetRecipient) GetDestAccount() string {
+	if x != nil {
+		return x.DestAccount
+	}
+	return ""
+}
+
+type PayloadRemoveRecipient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Recipient     string `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	DestAccount   string `protobuf:"bytes,3,opt,name=dest_account,json=destAccount,proto3" json:"dest_account,omitempty"`
+}
+
+func (x *PayloadRemoveRecipient) Reset() {
+	*x = PayloadRemoveRecipient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadRemoveRecipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadRemoveRecipient) ProtoMessage() {}
+
+func (x *PayloadRemoveRecipient) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadRemoveRecipient.ProtoReflect.Descriptor instead.
+func (*PayloadRemoveRecipient) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PayloadRemoveRecipient) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadRemoveRecipient) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *PayloadRemoveRecipient) GetDestAccount() string {
+	if x != nil {
+		return x.DestAccount
+	}
+	return ""
+}
+
+type PayloadListRecipient struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Recipient     string `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+}
+
+func (x *PayloadListRecipient) Reset() {
+	*x = PayloadListRecipient{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadListRecipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadListRecipient) ProtoMessage() {}
+
+func (x *PayloadListRecipient) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadListRecipient.ProtoReflect.Descriptor instead.
+func (*PayloadListRecipient) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PayloadListRecipient) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadListRecipient) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+type PayloadGrantRole struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	PermissionTag string `protobuf:"bytes,2,opt,name=permission_tag,json=permissionTag,proto3" json:"permission_tag,omitempty"`
+	PubKey        string `protobuf:"bytes,3,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Role          string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *PayloadGrantRole) Reset() {
+	*x = PayloadGrantRole{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadGrantRole) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadGrantRole) ProtoMessage() {}
+
+func (x *PayloadGrantRole) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadGrantRole.ProtoReflect.Descriptor instead.
+func (*PayloadGrantRole) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PayloadGrantRole) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadGrantRole) GetPermissionTag() string {
+	if x != nil {
+		return x.PermissionTag
+	}
+	return ""
+}
+
+func (x *PayloadGrantRole) GetPubKey() string {
+	if x != nil {
+		return x.PubKey
+	}
+	return ""
+}
+
+func (x *PayloadGrantRole) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type PayloadRevokeRole struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	PermissionTag string `protobuf:"bytes,2,opt,name=permission_tag,json=permissionTag,proto3" json:"permission_tag,omitempty"`
+	PubKey        string `protobuf:"bytes,3,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Role          string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *PayloadRevokeRole) Reset() {
+	*x = PayloadRevokeRole{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadRevokeRole) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadRevokeRole) ProtoMessage() {}
+
+func (x *PayloadRevokeRole) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadRevokeRole.ProtoReflect.Descriptor instead.
+func (*PayloadRevokeRole) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PayloadRevokeRole) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadRevokeRole) GetPermissionTag() string {
+	if x != nil {
+		return x.PermissionTag
+	}
+	return ""
+}
+
+func (x *PayloadRevokeRole) GetPubKey() string {
+	if x != nil {
+		return x.PubKey
+	}
+	return ""
+}
+
+func (x *PayloadRevokeRole) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type PayloadBatchStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	BatchId       string `protobuf:"bytes,2,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+}
+
+func (x *PayloadBatchStatus) Reset() {
+	*x = PayloadBatchStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadBatchStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadBatchStatus) ProtoMessage() {}
+
+func (x *PayloadBatchStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadBatchStatus.ProtoReflect.Descriptor instead.
+func (*PayloadBatchStatus) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PayloadBatchStatus) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadBatchStatus) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+type PayloadSetAccountPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Policy        string `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+}
+
+func (x *PayloadSetAccountPolicy) Reset() {
+	*x = PayloadSetAccountPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadSetAccountPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadSetAccountPolicy) ProtoMessage() {}
+
+func (x *PayloadSetAccountPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadSetAccountPolicy.ProtoReflect.Descriptor instead.
+func (*PayloadSetAccountPolicy) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PayloadSetAccountPolicy) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadSetAccountPolicy) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+type PayloadGetAccountPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+}
+
+func (x *PayloadGetAccountPolicy) Reset() {
+	*x = PayloadGetAccountPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadGetAccountPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadGetAccountPolicy) ProtoMessage() {}
+
+func (x *PayloadGetAccountPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadGetAccountPolicy.ProtoReflect.Descriptor instead.
+func (*PayloadGetAccountPolicy) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PayloadGetAccountPolicy) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+type PayloadSetHolidays struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string   `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Holidays      []string `protobuf:"bytes,2,rep,name=holidays,proto3" json:"holidays,omitempty"`
+}
+
+func (x *PayloadSetHolidays) Reset() {
+	*x = PayloadSetHolidays{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadSetHolidays) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadSetHolidays) ProtoMessage() {}
+
+func (x *PayloadSetHolidays) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadSetHolidays.ProtoReflect.Descriptor instead.
+func (*PayloadSetHolidays) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PayloadSetHolidays) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadSetHolidays) GetHolidays() []string {
+	if x != nil {
+		return x.Holidays
+	}
+	return nil
+}
+
+type PayloadListHolidays struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+}
+
+func (x *PayloadListHolidays) Reset() {
+	*x = PayloadListHolidays{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadListHolidays) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadListHolidays) ProtoMessage() {}
+
+func (x *PayloadListHolidays) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadListHolidays.ProtoReflect.Descriptor instead.
+func (*PayloadListHolidays) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PayloadListHolidays) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+// only the bank creates the 3 below
+type PayloadSetPendingTx struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount   string   `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	BankTransaction []byte   `protobuf:"bytes,2,opt,name=bank_transaction,json=bankTransaction,proto3" json:"bank_transaction,omitempty"`
+	AuthorisedSigs  []string `protobuf:"bytes,3,rep,name=authorised_sigs,json=authorisedSigs,proto3" json:"authorised_sigs,omitempty"`
+	RequiredMinSigs []int32  `protobuf:"varint,4,rep,packed,name=required_min_sigs,json=requiredMinSigs,proto3" json:"required_min_sigs,omitempty"`
+	TransactionId   string   `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Initiator       string   `protobuf:"bytes,6,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	QueryTime       int64    `protobuf:"varint,7,opt,name=query_time,json=queryTime,proto3" json:"query_time,omitempty"`
+}
+
+func (x *PayloadSetPendingTx) Reset() {
+	*x = PayloadSetPendingTx{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadSetPendingTx) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadSetPendingTx) ProtoMessage() {}
+
+func (x *PayloadSetPendingTx) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadSetPendingTx.ProtoReflect.Descriptor instead.
+func (*PayloadSetPendingTx) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PayloadSetPendingTx) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadSetPendingTx) GetBankTransaction() []byte {
+	if x != nil {
+		return x.BankTransaction
+	}
+	return nil
+}
+
+func (x *PayloadSetPendingTx) GetAuthorisedSigs() []string {
+	if x != nil {
+		return x.AuthorisedSigs
+	}
+	return nil
+}
+
+func (x *PayloadSetPendingTx) GetRequiredMinSigs() []int32 {
+	if x != nil {
+		return x.RequiredMinSigs
+	}
+	return nil
+}
+
+func (x *PayloadSetPendingTx) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PayloadSetPendingTx) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *PayloadSetPendingTx) GetQueryTime() int64 {
+	if x != nil {
+		return x.QueryTime
+	}
+	return 0
+}
+
+type PayloadRecordSpend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string  `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Amount        float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Time          int64   `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *PayloadRecordSpend) Reset() {
+	*x = PayloadRecordSpend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadRecordSpend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadRecordSpend) ProtoMessage() {}
+
+func (x *PayloadRecordSpend) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadRecordSpend.ProtoReflect.Descriptor instead.
+func (*PayloadRecordSpend) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PayloadRecordSpend) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadRecordSpend) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PayloadRecordSpend) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+type PayloadPurgeExpiredRules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SourceAccount string   `protobuf:"bytes,1,opt,name=source_account,json=sourceAccount,proto3" json:"source_account,omitempty"`
+	Addresses     []string `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Time          int64    `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *PayloadPurgeExpiredRules) Reset() {
+	*x = PayloadPurgeExpiredRules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_payloads_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadPurgeExpiredRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadPurgeExpiredRules) ProtoMessage() {}
+
+func (x *PayloadPurgeExpiredRules) ProtoReflect() protoreflect.Message {
+	mi := &file_payloads_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadPurgeExpiredRules.ProtoReflect.Descriptor instead.
+func (*PayloadPurgeExpiredRules) Descriptor() ([]byte, []int) {
+	return file_payloads_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PayloadPurgeExpiredRules) GetSourceAccount() string {
+	if x != nil {
+		return x.SourceAccount
+	}
+	return ""
+}
+
+func (x *PayloadPurgeExpiredRules) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *PayloadPurgeExpiredRules) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+var File_payloads_proto protoreflect.FileDescriptor
+
+var file_payloads_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x04, 0x62, 0x61, 0x6e, 0x6b, 0x22, 0xea, 0x01, 0x0a, 0x17, 0x50, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x75,
+	0x6c, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x61,
+	0x6c, 0x69, 0x64, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70,
+	0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x76, 0x65, 0x72, 0x72,
+	0x69, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x76, 0x65, 0x72, 0x72,
+	0x69, 0x64, 0x65, 0x22, 0x7e, 0x0a, 0x1a, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x75, 0x6c, 0x65, 0x48,
+	0x61, 0x73, 0x68, 0x22, 0x60, 0x0a, 0x19, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4c, 0x69,
+	0x73, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x75, 0x6c, 0x65, 0x73,
+	0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x61, 0x0a, 0x1a, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x62, 0x0a, 0x1b, 0x50, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72,
+	0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x77, 0x0a, 0x1a,
+	0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x74, 0x6f, 0x72, 0x54, 0x6f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x7c, 0x0a, 0x1f, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x46,
+	0x72, 0x6f, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x22, 0x7c, 0x0a, 0x1a, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65,
+	0x74, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65,
+	0x79, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x73, 0x22, 0x7f, 0x0a, 0x1d, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x75, 0x62, 0x5f, 0x6b,
+	0x65, 0x79, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x70, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x73, 0x22, 0xc8, 0x01, 0x0a, 0x10, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x41, 0x75, 0x74, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09,
+	0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65,
+	0x73, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x84, 0x02,
+	0x0a, 0x13, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x69, 0x6d, 0x75, 0x6c, 0x61, 0x74,
+	0x65, 0x41, 0x75, 0x74, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x73, 0x74,
+	0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x0b, 0x65,
+	0x78, 0x74, 0x72, 0x61, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x2e, 0x48, 0x79, 0x70, 0x6f, 0x74, 0x68, 0x65, 0x74,
+	0x69, 0x63, 0x61, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x22, 0x44, 0x0a, 0x10, 0x48, 0x79, 0x70, 0x6f, 0x74, 0x68, 0x65, 0x74,
+	0x69, 0x63, 0x61, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x22, 0xa8, 0x01, 0x0a, 0x15, 0x50,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x50, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x54, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72,
+	0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74,
+	0x6f, 0x72, 0x4b, 0x65, 0x79, 0x22, 0xc8, 0x01, 0x0a, 0x0f, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x41, 0x64, 0x64, 0x53, 0x69, 0x67, 0x54, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x1c,
+	0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x22, 0x5b, 0x0a, 0x14, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x7d, 0x0a,
+	0x13, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x74, 0x52, 0x65, 0x63, 0x69, 0x70,
+	0x69, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72,
+	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x73,
+	0x74, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x80, 0x01, 0x0a,
+	0x16, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x64, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22,
+	0x5b, 0x0a, 0x14, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x8d, 0x01, 0x0a,
+	0x10, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x47, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x6f, 0x6c,
+	0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x65, 0x72, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x61, 0x67, 0x12,
+	0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x8e, 0x01, 0x0a,
+	0x11, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x6f,
+	0x6c, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x65, 0x72,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x61, 0x67,
+	0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x56, 0x0a,
+	0x12, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61,
+	0x74, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61,
+	0x74, 0x63, 0x68, 0x49, 0x64, 0x22, 0x58, 0x0a, 0x17, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x53, 0x65, 0x74, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22,
+	0x40, 0x0a, 0x17, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x47, 0x65, 0x74, 0x41, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x57, 0x0a, 0x12, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x74, 0x48,
+	0x6f, 0x6c, 0x69, 0x64, 0x61, 0x79, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x68, 0x6f, 0x6c, 0x69, 0x64, 0x61, 0x79, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x68, 0x6f, 0x6c, 0x69, 0x64, 0x61, 0x79, 0x73, 0x22, 0x3c, 0x0a, 0x13, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x48, 0x6f, 0x6c, 0x69, 0x64, 0x61, 0x79,
+	0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xa0, 0x02, 0x0a, 0x13, 0x50, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x78,
+	0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x61, 0x6e, 0x6b, 0x5f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0f, 0x62, 0x61, 0x6e, 0x6b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x73, 0x65, 0x64,
+	0x5f, 0x73, 0x69, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x69, 0x73, 0x65, 0x64, 0x53, 0x69, 0x67, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64,
+	0x4d, 0x69, 0x6e, 0x53, 0x69, 0x67, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1c,
+	0x0a, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x71, 0x75, 0x65, 0x72, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x67, 0x0a, 0x12, 0x50,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x53, 0x70, 0x65, 0x6e,
+	0x64, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x22, 0x73, 0x0a, 0x18, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x50,
+	0x75, 0x72, 0x67, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x52, 0x75, 0x6c, 0x65, 0x73,
+	0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x12, 0x5a, 0x10, 0x2e, 0x2e, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_payloads_proto_rawDescOnce sync.Once
+	file_payloads_proto_rawDescData = file_payloads_proto_rawDesc
+)
+
+func file_payloads_proto_rawDescGZIP() []byte {
+	file_payloads_proto_rawDescOnce.Do(func() {
+		file_payloads_proto_rawDescData = protoimpl.X.CompressGZIP(file_payloads_proto_rawDescData)
+	})
+	return file_payloads_proto_rawDescData
+}
+
+var file_payloads_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_payloads_proto_goTypes = []any{
+	(*PayloadSetInitiatorRule)(nil),         // 0: bank.PayloadSetInitiatorRule
+	(*PayloadDeleteInitiatorRule)(nil),      // 1: bank.PayloadDeleteInitiatorRule
+	(*PayloadListInitiatorRules)(nil),       // 2: bank.PayloadListInitiatorRules
+	(*PayloadListInitiatorGroups)(nil),      // 3: bank.PayloadListInitiatorGroups
+	(*PayloadListInitiatorPubKeys)(nil),     // 4: bank.PayloadListInitiatorPubKeys
+	(*PayloadAddInitiatorToGroup)(nil),      // 5: bank.PayloadAddInitiatorToGroup
+	(*PayloadRemoveInitiatorFromGroup)(nil), // 6: bank.PayloadRemoveInitiatorFromGroup
+	(*PayloadSetInitiatorPubKeys)(nil),      // 7: bank.PayloadSetInitiatorPubKeys
+	(*PayloadDeleteInitiatorPubKeys)(nil),   // 8: bank.PayloadDeleteInitiatorPubKeys
+	(*PayloadQueryAuth)(nil),                // 9: bank.PayloadQueryAuth
+	(*PayloadSimulateAuth)(nil),             // 10: bank.PayloadSimulateAuth
+	(*HypotheticalRule)(nil),                // 11: bank.HypotheticalRule
+	(*PayloadClosePendingTx)(nil),           // 12: bank.PayloadClosePendingTx
+	(*PayloadAddSigTx)(nil),                 // 13: bank.PayloadAddSigTx
+	(*PayloadListPendingTx)(nil),            // 14: bank.PayloadListPendingTx
+	(*PayloadSetRecipient)(nil),             // 15: bank.PayloadSetRecipient
+	(*PayloadRemoveRecipient)(nil),          // 16: bank.PayloadRemoveRecipient
+	(*PayloadListRecipient)(nil),            // 17: bank.PayloadListRecipient
+	(*PayloadGrantRole)(nil),                // 18: bank.PayloadGrantRole
+	(*PayloadRevokeRole)(nil),               // 19: bank.PayloadRevokeRole
+	(*PayloadBatchStatus)(nil),              // 20: bank.PayloadBatchStatus
+	(*PayloadSetAccountPolicy)(nil),         // 21: bank.PayloadSetAccountPolicy
+	(*PayloadGetAccountPolicy)(nil),         // 22: bank.PayloadGetAccountPolicy
+	(*PayloadSetHolidays)(nil),              // 23: bank.PayloadSetHolidays
+	(*PayloadListHolidays)(nil),             // 24: bank.PayloadListHolidays
+	(*PayloadSetPendingTx)(nil),             // 25: bank.PayloadSetPendingTx
+	(*PayloadRecordSpend)(nil),              // 26: bank.PayloadRecordSpend
+	(*PayloadPurgeExpiredRules)(nil),        // 27: bank.PayloadPurgeExpiredRules
+}
+var file_payloads_proto_depIdxs = []int32{
+	11, // 0: bank.PayloadSimulateAuth.extra_rules:type_name -> bank.HypotheticalRule
+	1,  // [1:1] is the sub-list for method output_type
+	1,  // [1:1] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_payloads_proto_init() }
+func file_payloads_proto_init() {
+	if File_payloads_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_payloads_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadSetInitiatorRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadDeleteInitiatorRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadListInitiatorRules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadListInitiatorGroups); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadListInitiatorPubKeys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadAddInitiatorToGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadRemoveInitiatorFromGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadSetInitiatorPubKeys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadDeleteInitiatorPubKeys); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadQueryAuth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadSimulateAuth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*HypotheticalRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadClosePendingTx); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadAddSigTx); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadListPendingTx); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadSetRecipient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadRemoveRecipient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadListRecipient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadGrantRole); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadRevokeRole); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadBatchStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadSetAccountPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadGetAccountPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadSetHolidays); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadListHolidays); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadSetPendingTx); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadRecordSpend); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_payloads_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*PayloadPurgeExpiredRules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_payloads_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_payloads_proto_goTypes,
+		DependencyIndexes: file_payloads_proto_depIdxs,
+		MessageInfos:      file_payloads_proto_msgTypes,
+	}.Build()
+	File_payloads_proto = out.File
+	file_payloads_proto_rawDesc = nil
+	file_payloads_proto_goTypes = nil
+	file_payloads_proto_depIdxs = nil
+}
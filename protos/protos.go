@@ -0,0 +1,4 @@
+// Package protos has the protobuf definitions of the payloads and of the data in the state. Regenerate the .pb.go files after changing a .proto file
+package protos
+
+//go:generate protoc --go_out=paths=source_relative:. payloads.proto state.proto
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	flags "github.com/jessevdk/go-flags"
+
+	"../core"
+)
+
+// Opts is for parsing command line options
+type Opts struct {
+	Accounts []string `short:"s" long:"sourceaccount" description:"account to purge expired rules from. repeat for every account" required:"true"`
+}
+
+// purge deletes the rules that have expired, i.e., whose ValidUntil has passed, from the state of accounts. expired rules are never evaluated, this only keeps the state and rule listings short. meant to be run periodically by the bank, which signs the transactions
+func main() {
+	var opts Opts
+
+	parser := flags.NewParser(&opts, flags.Default)
+	_, err := parser.Parse()
+	if err != nil {
+		os.Exit(1)
+	}
+
+	// account => hashes of the purged rules
+	purged := make(map[string][]string, len(opts.Accounts))
+	for _, a := range opts.Accounts {
+		hashes, err := core.PurgeExpiredRules(a)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, a+": ", err)
+			os.Exit(1)
+		}
+		purged[a] = hashes
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(purged)
+	if err != nil {
+		panic(err)
+	}
+}
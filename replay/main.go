@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+
+	c "../common"
+	"../core"
+)
+
+// Opts is for parsing command line options
+type Opts struct {
+	Transactions string   `short:"t" long:"transactions" description:"JSONL file of query_auth payloads with the unix time they were queried at in \"time\", one banking transaction per line" required:"true"`
+	Snapshot     string   `short:"s" long:"snapshot" description:"state snapshot: a response from the rest api state endpoint saved to a file" required:"true"`
+	Add          []string `short:"a" long:"add" description:"proposed rule, as initiator=rule, e.g., \"Everyone=Amount > 10000 ? 'deny' : 'nil'\" for an account level rule. repeat for every rule"`
+	Remove       []string `short:"r" long:"remove" description:"hash of a rule to take out. repeat for every rule"`
+	CoreBanking  string   `short:"b" long:"corebanking" description:"core banking file with account balances, for rules on Balance"`
+}
+
+// replay reports how proposed rules would have changed the decisions on past banking transactions. It reads the state from a snapshot, never from the rest api
+func main() {
+	var opts Opts
+
+	parser := flags.NewParser(&opts, flags.Default)
+	_, err := parser.Parse()
+	if err != nil {
+		os.Exit(1)
+	}
+
+	proposed := make([]c.HypotheticalRule, len(opts.Add))
+	for i, a := range opts.Add {
+		// initiators don't have '=' in their names, rules might
+		s := strings.SplitN(a, "=", 2)
+		if len(s) != 2 {
+			fmt.Fprintln(os.Stderr, "proposed rule must be given as initiator=rule: "+a)
+			os.Exit(1)
+		}
+		proposed[i] = c.HypotheticalRule{Initiator: s[0], Rule: s[1]}
+	}
+
+	snapshot, err := core.LoadStateSnapshot(opts.Snapshot)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if opts.CoreBanking != "" {
+		cbs, err := core.NewFileCoreBanking(opts.CoreBanking)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		core.SetCoreBanking(cbs)
+	}
+	queries, err := readTransactions(opts.Transactions)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	report, err := core.Replay(snapshot, queries, proposed, opts.Remove)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// no html escaping: changes read allow->deny
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(report)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// readTransactions the banking transactions in the JSONL file fileName. blank lines are skipped
+func readTransactions(fileName string) ([]core.ReplayTransaction, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ret := make([]core.ReplayTransaction, 0)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var q core.ReplayTransaction
+		err = json.Unmarshal([]byte(line), &q)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: malformed transaction: %v", fileName, n, err)
+		}
+		ret = append(ret, q)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -1,8 +1,8 @@
 package tprocessor
 
 import (
-	"encoding/json"
-	"reflect"
+	"fmt"
+	"sync"
 	"syscall"
 
 	c "../common"
@@ -15,6 +15,9 @@ import (
 var familyNameReferenceCount = make(map[string]int, 0)
 var familyNameToProcessor = make(map[string]*processor.TransactionProcessor, 0)
 
+// processorsMu guards familyNameReferenceCount and familyNameToProcessor: requests of the lambda launch and shut down processors concurrently. not held while a processor runs, Start() only returns on shutdown
+var processorsMu sync.Mutex
+
 // Opts is for parsing command line options
 type Opts struct {
 	FamilyName string `short:"f" long:"family_name" description:"transaction family name, the source account number at this time"`
@@ -22,8 +25,8 @@ type Opts struct {
 
 // TpHandler is the handler to register with the validator
 type TpHandler struct {
-	TpName    string // this is to be used as the transactions family name
-	TpVersion string // this the family version
+	TpName     string   // this is to be used as the transactions family name
+	TpVersions []string // the family versions, see core.FamilyVersions
 }
 
 // FamilyName returns prefix for transactions handled by this handler
@@ -33,7 +36,7 @@ func (r *TpHandler) FamilyName() string {
 
 // FamilyVersions as the name says...
 func (r *TpHandler) FamilyVersions() []string {
-	return []string{r.TpVersion}
+	return r.TpVersions
 }
 
 // Namespaces for transactions
@@ -42,37 +45,40 @@ func (r *TpHandler) Namespaces() []string {
 }
 
 // Apply is the main logic
-func (r *TpHandler) Apply(tx *processor_pb2.TpProcessRequest, context *processor.Context) error {
-	var p c.SignedPayload
-	err := json.Unmarshal(tx.Payload, &p)
+func (r *TpHandler) Apply(tx *processor_pb2.TpProcessRequest, context *processor.Context) (err error) {
+	// a panic must not kill the transaction processor
+	defer func() {
+		if r := recover(); r != nil {
+			err = &processor.InternalError{Msg: fmt.Sprintf("%v", r)}
+		}
+	}()
+
+	err = core.ApplyTransaction(tx.Header, tx.Payload, context)
 	if err != nil {
-		panic(err)
+		return toProcessorError(err)
 	}
 
-	t := core.PayloadRegistry[p.Type]
-	f := reflect.New(t).Elem().MethodByName("Apply")
-	if f.IsValid() {
-		v := reflect.ValueOf(p.Payload)
-		c := reflect.ValueOf(context)
-		ar := f.Call([]reflect.Value{v, c})
-		e := ar[0].Interface() // TODO TODO debug replaces this e := ar[0].Interface()
-
-		if e != nil {
-			return e.(error)
-		}
+	return nil
+}
 
-		return nil
+// toProcessorError tells the validator what to do with the transaction: invalid transactions are rejected for good, internal errors are retried
+func toProcessorError(err error) error {
+	switch core.KindOf(err) {
+	case core.KindValidation, core.KindAuthorization, core.KindNotFound:
+		return &processor.InvalidTransactionError{Msg: err.Error()}
+	default:
+		return &processor.InternalError{Msg: err.Error()}
 	}
-	// we should not conceivably get here since we check for Apply() method in lambda handler
-	panic("payload in request does not have Apply() method")
 }
 
 // Launch launch transaction processor for this family name
 func Launch(familyName string) {
+	processorsMu.Lock()
 	familyNameReferenceCount[familyName]++
 	n := familyNameReferenceCount[familyName]
 	if n != 1 {
 		// already running: we increase reference count by one and return. TODO later implement logic like familyNameReferenceCount == Threshold => launch new transaction processor?
+		processorsMu.Unlock()
 		return
 	}
 
@@ -81,11 +87,12 @@ func Launch(familyName string) {
 	// TODO TODO TODO should we check if a transaction processor for this account is already running?
 	processor := processor.NewTransactionProcessor(c.ValidatorEndpoint)
 	familyNameToProcessor[familyName] = processor
+	processorsMu.Unlock()
 
 	processor.ShutdownOnSignal(syscall.SIGINT, syscall.SIGTERM)
 	p := &TpHandler{
-		TpName:    familyName,
-		TpVersion: core.FamilyVersion,
+		TpName:     familyName,
+		TpVersions: core.FamilyVersions,
 	}
 	// TODO TODO TODO TODO TODO for debugging for debugging for debugging
 	processor.SetThreadCount(1)
@@ -100,12 +107,18 @@ func Launch(familyName string) {
 
 // ShutDown decrement reference count and shutdown transaction processor for family name if 0
 func ShutDown(familyName string) {
+	processorsMu.Lock()
+	defer processorsMu.Unlock()
+
 	familyNameReferenceCount[familyName]--
 	n := familyNameReferenceCount[familyName]
 	if n == 0 {
 		// reference count down to 0 so shut down
-		p := familyNameToProcessor[familyName]
-		p.Shutdown()
+		p, ok := familyNameToProcessor[familyName]
+		if ok {
+			delete(familyNameToProcessor, familyName)
+			p.Shutdown()
+		}
 	}
 
 	return
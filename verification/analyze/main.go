@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	flags "github.com/jessevdk/go-flags"
+
+	// core registers the rule functions, e.g., NofM
+	_ "../../core"
+	v "../../verification"
+)
+
+// Opts is for parsing command line options
+type Opts struct {
+	Rules []string `short:"r" long:"rule" description:"rule to analyze, e.g., \"Amount > 10000 ? 'deny' : 'nil'\". repeat for every rule"`
+}
+
+// analyze checks whether the rules on the command line can all fire together. Rules are identified by their position on the command line in the output
+func main() {
+	var opts Opts
+
+	parser := flags.NewParser(&opts, flags.Default)
+	_, err := parser.Parse()
+	if err != nil {
+		os.Exit(1)
+	}
+
+	rules := make([]v.Rule, len(opts.Rules))
+	for i, r := range opts.Rules {
+		rules[i] = v.Rule{Hash: strconv.Itoa(i + 1), Rule: r}
+	}
+
+	res, err := v.Analyze(rules)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	b, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+}
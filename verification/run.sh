@@ -1 +1 @@
-LD_LIBRARY_PATH=. go run main.go z3.go
+LD_LIBRARY_PATH=. go run analyze/main.go -r "Amount > 10000 ? NofM(2, 'ID12345, CD34YG4') : 'nil'" -r "Amount < 5000 ? 'deny' : 'nil'"
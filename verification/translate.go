@@ -0,0 +1,540 @@
+package verification
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Knetic/govaluate"
+
+	c "../common"
+)
+
+// SMT sorts of translated expressions
+const (
+	sortBool    = c.SortBool
+	sortReal    = c.SortReal
+	sortString  = c.SortString
+	sortOutcome = c.SortOutcome
+)
+
+// the values a rule returns. 'nil', 'deny' and 'allow' are strings in rules, NofM() a rule function
+const outcomeDatatype = "(declare-datatypes ((Outcome 0)) (((Nil) (Deny) (Allow) (NofM (required Real) (signers String)))))"
+
+// maps the outcome strings in rules to the constructors in outcomeDatatype
+var outcomeStrings = map[string]string{
+	"nil":   "Nil",
+	"deny":  "Deny",
+	"allow": "Allow",
+}
+
+// rule functions that build an outcome rather than compute a value are constructors of outcomeDatatype
+var outcomeConstructors = map[string]bool{
+	"NofM": true,
+}
+
+// =~ and !~ are translated to this uninterpreted function: z3 is free to decide whether a string matches a pattern
+const matchesDecl = "(declare-fun matches (String String) Bool)"
+
+// Translation of a (govaluate) expression, typically a rule, into an SMT-LIB 2 term
+type Translation struct {
+	Term    string   // the SMT-LIB 2 term
+	Sort    string   // its sort. a rule is of sort Outcome, a predicate of sort Bool
+	Vars    []string // rule variables in the expression
+	Funcs   []string // rule functions in the expression that are declared in the program, i.e., not outcome functions like NofM
+	MayDeny bool     // a rule that may return 'deny'
+
+	matches bool // uses the matches function
+}
+
+// Translate translates a govaluate expression into an SMT-LIB 2 term. Expressions are read from govaluate's token stream so anything govaluate takes for a rule is translated the same way it is evaluated, except for a few operators that have no SMT counterpart (bitwise operators, ??, %). Ternaries become ite terms and the strings 'nil', 'deny' and 'allow' returned by rules, as well as NofM(), become values of the Outcome datatype
+func Translate(expr string) (Translation, error) {
+	// the registered rule functions let govaluate tell function calls from variables
+	names := c.RuleFunctionNames()
+	tagged := make(map[string]govaluate.ExpressionFunction, len(names))
+	for _, name := range names {
+		tagged[name] = tagFunction(name)
+	}
+
+	e, err := c.ParseRule(expr, tagged)
+	if err != nil {
+		return Translation{}, err
+	}
+
+	p := &tokenParser{tokens: e.Tokens()}
+	n, err := p.parseTernary()
+	if err != nil {
+		return Translation{}, err
+	}
+	if p.pos != len(p.tokens) {
+		return Translation{}, errors.New("unexpected token " + fmt.Sprint(p.tokens[p.pos].Value))
+	}
+
+	t := &Translation{}
+	vars, funcs := make(map[string]bool), make(map[string]bool)
+	t.Term, t.Sort, err = t.emit(n, sortOutcome, vars, funcs)
+	if err != nil {
+		return Translation{}, err
+	}
+	for v := range vars {
+		t.Vars = append(t.Vars, v)
+	}
+	for f := range funcs {
+		t.Funcs = append(t.Funcs, f)
+	}
+
+	return *t, nil
+}
+
+// Fires is the SMT-LIB 2 condition for a rule bound to name (see CreateNamedSMTprogram) to fire, i.e., to return something other than 'nil'
+func Fires(name string) string {
+	return "(not (= " + name + " Nil))"
+}
+
+// Denies is the SMT-LIB 2 condition for a rule bound to name to return 'deny'
+func Denies(name string) string {
+	return "(= " + name + " Deny)"
+}
+
+// functions are identified by their value in govaluate's token stream. we can't compare functions in go so we call them instead: a tagged function called with a tag returns its name
+type tag struct{}
+
+func tagFunction(name string) govaluate.ExpressionFunction {
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) == 1 {
+			if _, ok := args[0].(tag); ok {
+				return name, nil
+			}
+		}
+		return nil, errors.New("rule functions are not evaluated in translations")
+	}
+}
+
+func functionName(f interface{}) string {
+	fn, ok := f.(govaluate.ExpressionFunction)
+	if !ok {
+		return ""
+	}
+	name, _ := fn(tag{})
+	s, _ := name.(string)
+
+	return s
+}
+
+// node of the expression tree built out of govaluate tokens
+type node struct {
+	kind     govaluate.TokenKind
+	op       string      // operator or function name
+	value    interface{} // literals and variable names
+	children []*node
+}
+
+type tokenParser struct {
+	tokens []govaluate.ExpressionToken
+	pos    int
+}
+
+func (p *tokenParser) peek(kind govaluate.TokenKind, values ...string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].Kind != kind {
+		return false
+	}
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if p.tokens[p.pos].Value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *tokenParser) next() govaluate.ExpressionToken {
+	t := p.tokens[p.pos]
+	p.pos++
+
+	return t
+}
+
+// lowest precedence first, as in govaluate. govaluate evaluates ? and : left to right, so a ? b : c ? d : e is (a ? b : c) ? d : e, not a ? b : (c ? d : e). the tree is built the same way: chains that govaluate can only evaluate for some inputs are rejected when emitted, and a : that doesn't close a ? is rejected here
+func (p *tokenParser) parseTernary() (*node, error) {
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek(govaluate.TERNARY) {
+		op := p.next().Value.(string)
+		if op == "??" {
+			return nil, errors.New("?? is not supported")
+		}
+		operand, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if op == "?" {
+			n = &node{kind: govaluate.TERNARY, children: []*node{n, operand}}
+			continue
+		}
+		if n.kind != govaluate.TERNARY || len(n.children) != 2 {
+			return nil, errors.New(": without a matching ?")
+		}
+		n.children = append(n.children, operand)
+	}
+
+	return n, nil
+}
+
+func (p *tokenParser) parseOr() (*node, error) {
+	return p.parseBinary(govaluate.LOGICALOP, []string{"||"}, p.parseAnd)
+}
+
+func (p *tokenParser) parseAnd() (*node, error) {
+	return p.parseBinary(govaluate.LOGICALOP, []string{"&&"}, p.parseComparison)
+}
+
+func (p *tokenParser) parseComparison() (*node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek(govaluate.COMPARATOR) {
+		op := p.next().Value.(string)
+		var right *node
+		if op == "in" {
+			right, err = p.parseList()
+		} else {
+			right, err = p.parseAdditive()
+		}
+		if err != nil {
+			return nil, err
+		}
+		left = &node{kind: govaluate.COMPARATOR, op: op, children: []*node{left, right}}
+	}
+
+	return left, nil
+}
+
+func (p *tokenParser) parseAdditive() (*node, error) {
+	return p.parseBinary(govaluate.MODIFIER, []string{"+", "-", "&", "|", "^", "<<", ">>"}, p.parseMultiplicative)
+}
+
+func (p *tokenParser) parseMultiplicative() (*node, error) {
+	return p.parseBinary(govaluate.MODIFIER, []string{"*", "/", "%"}, p.parseExponent)
+}
+
+func (p *tokenParser) parseExponent() (*node, error) {
+	return p.parseBinary(govaluate.MODIFIER, []string{"**"}, p.parsePrefix)
+}
+
+func (p *tokenParser) parseBinary(kind govaluate.TokenKind, ops []string, operand func() (*node, error)) (*node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek(kind, ops...) {
+		op := p.next().Value.(string)
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &node{kind: kind, op: op, children: []*node{left, right}}
+	}
+
+	return left, nil
+}
+
+func (p *tokenParser) parsePrefix() (*node, error) {
+	if !p.peek(govaluate.PREFIX) {
+		return p.parsePrimary()
+	}
+	op := p.next().Value.(string)
+	operand, err := p.parsePrefix()
+	if err != nil {
+		return nil, err
+	}
+
+	return &node{kind: govaluate.PREFIX, op: op, children: []*node{operand}}, nil
+}
+
+func (p *tokenParser) parsePrimary() (*node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+
+	t := p.next()
+	switch t.Kind {
+	case govaluate.NUMERIC, govaluate.STRING, govaluate.BOOLEAN, govaluate.TIME, govaluate.PATTERN, govaluate.VARIABLE:
+		return &node{kind: t.Kind, value: t.Value}, nil
+	case govaluate.FUNCTION:
+		name := functionName(t.Value)
+		if name == "" {
+			return nil, errors.New("unknown rule function")
+		}
+		args, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: govaluate.FUNCTION, op: name, children: args.children}, nil
+	case govaluate.CLAUSE:
+		n, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(govaluate.CLAUSE_CLOSE) {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.next()
+		return n, nil
+	}
+
+	return nil, errors.New("unexpected token " + fmt.Sprint(t.Value))
+}
+
+// parenthesized, comma-separated list, as in function arguments or the right hand side of IN
+func (p *tokenParser) parseList() (*node, error) {
+	if !p.peek(govaluate.CLAUSE) {
+		return nil, errors.New("expected (")
+	}
+	p.next()
+
+	n := &node{kind: govaluate.SEPARATOR}
+	if p.peek(govaluate.CLAUSE_CLOSE) {
+		p.next()
+		return n, nil
+	}
+	for {
+		item, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		n.children = append(n.children, item)
+		if p.peek(govaluate.SEPARATOR) {
+			p.next()
+			continue
+		}
+		if !p.peek(govaluate.CLAUSE_CLOSE) {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.next()
+		return n, nil
+	}
+}
+
+// emit the SMT-LIB 2 term for n. hint is the sort expected by the context: strings are outcomes when an outcome is expected
+func (t *Translation) emit(n *node, hint string, vars, funcs map[string]bool) (string, string, error) {
+	switch n.kind {
+	case govaluate.NUMERIC:
+		return realLiteral(n.value.(float64)), sortReal, nil
+	case govaluate.BOOLEAN:
+		return strconv.FormatBool(n.value.(bool)), sortBool, nil
+	case govaluate.TIME:
+		// times are compared as seconds since the epoch
+		return realLiteral(float64(n.value.(time.Time).Unix())), sortReal, nil
+	case govaluate.PATTERN:
+		return stringLiteral(n.value.(*regexp.Regexp).String()), sortString, nil
+	case govaluate.STRING:
+		s := n.value.(string)
+		if hint != sortOutcome {
+			return stringLiteral(s), sortString, nil
+		}
+		o, ok := outcomeStrings[s]
+		if !ok {
+			return "", "", errors.New("unknown rule outcome '" + s + "'")
+		}
+		if o == "Deny" {
+			t.MayDeny = true
+		}
+		return o, sortOutcome, nil
+	case govaluate.VARIABLE:
+		name := n.value.(string)
+		sort, ok := RuleVariablesSet[name]
+		if !ok {
+			return "", "", errors.New("no SMT type for variable " + name)
+		}
+		vars[name] = true
+		return name, sort, nil
+	case govaluate.TERNARY:
+		cond, condSort, err := t.emit(n.children[0], sortBool, vars, funcs)
+		if err != nil {
+			return "", "", err
+		}
+		if condSort != sortBool {
+			return "", "", errors.New("condition of ternary is not a boolean")
+		}
+		then, sort, err := t.emit(n.children[1], hint, vars, funcs)
+		if err != nil {
+			return "", "", err
+		}
+		// govaluate returns nil when there is no else branch, which is neither an outcome nor a value
+		if len(n.children) != 3 {
+			return "", "", errors.New("ternary without else branch")
+		}
+		els, elsSort, err := t.emit(n.children[2], sort, vars, funcs)
+		if err != nil {
+			return "", "", err
+		}
+		if elsSort != sort {
+			return "", "", errors.New("branches of ternary are not of the same sort")
+		}
+		return "(ite " + cond + " " + then + " " + els + ")", sort, nil
+	case govaluate.LOGICALOP:
+		return t.emitOperation(map[string]string{"&&": "and", "||": "or"}[n.op], sortBool, sortBool, n.children, vars, funcs)
+	case govaluate.COMPARATOR:
+		return t.emitComparison(n, vars, funcs)
+	case govaluate.MODIFIER:
+		switch n.op {
+		case "+":
+			left, sort, err := t.emit(n.children[0], sortReal, vars, funcs)
+			if err != nil {
+				return "", "", err
+			}
+			right, _, err := t.emit(n.children[1], sort, vars, funcs)
+			if err != nil {
+				return "", "", err
+			}
+			if sort == sortString {
+				return "(str.++ " + left + " " + right + ")", sortString, nil
+			}
+			return "(+ " + left + " " + right + ")", sortReal, nil
+		case "-", "*", "/":
+			return t.emitOperation(n.op, sortReal, sortReal, n.children, vars, funcs)
+		case "**":
+			return t.emitOperation("^", sortReal, sortReal, n.children, vars, funcs)
+		}
+		return "", "", errors.New("operator " + n.op + " is not supported")
+	case govaluate.PREFIX:
+		switch n.op {
+		case "-":
+			return t.emitOperation("-", sortReal, sortReal, n.children, vars, funcs)
+		case "!":
+			return t.emitOperation("not", sortBool, sortBool, n.children, vars, funcs)
+		}
+		return "", "", errors.New("operator " + n.op + " is not supported")
+	case govaluate.FUNCTION:
+		f, ok := c.LookupRuleFunction(n.op)
+		if !ok {
+			return "", "", errors.New("unknown rule function " + n.op)
+		}
+		if len(n.children) != len(f.Args) {
+			return "", "", fmt.Errorf("%s takes %d arguments", n.op, len(f.Args))
+		}
+		if f.Returns == sortOutcome {
+			if !outcomeConstructors[n.op] {
+				return "", "", errors.New("no Outcome constructor for " + n.op)
+			}
+		} else {
+			// declared in the program
+			funcs[n.op] = true
+		}
+		if len(n.children) == 0 {
+			return n.op, f.Returns, nil
+		}
+		s := "(" + n.op
+		for i, arg := range n.children {
+			a, _, err := t.emit(arg, f.Args[i], vars, funcs)
+			if err != nil {
+				return "", "", err
+			}
+			s += " " + a
+		}
+		return s + ")", f.Returns, nil
+	}
+
+	return "", "", errors.New("expression not supported")
+}
+
+// (op children...) with operands expected of sort operandSort and result of sort sort
+func (t *Translation) emitOperation(op, operandSort, sort string, children []*node, vars, funcs map[string]bool) (string, string, error) {
+	s := "(" + op
+	for _, c := range children {
+		a, _, err := t.emit(c, operandSort, vars, funcs)
+		if err != nil {
+			return "", "", err
+		}
+		s += " " + a
+	}
+
+	return s + ")", sort, nil
+}
+
+func (t *Translation) emitComparison(n *node, vars, funcs map[string]bool) (string, string, error) {
+	left, sort, err := t.emit(n.children[0], "", vars, funcs)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch n.op {
+	case "in":
+		items := make([]string, 0, len(n.children[1].children))
+		for _, c := range n.children[1].children {
+			item, _, err := t.emit(c, sort, vars, funcs)
+			if err != nil {
+				return "", "", err
+			}
+			items = append(items, "(= "+left+" "+item+")")
+		}
+		if len(items) == 0 {
+			return "false", sortBool, nil
+		}
+		return "(or " + strings.Join(items, " ") + ")", sortBool, nil
+	case "=~", "!~":
+		right, _, err := t.emit(n.children[1], sortString, vars, funcs)
+		if err != nil {
+			return "", "", err
+		}
+		t.matches = true
+		m := "(matches " + left + " " + right + ")"
+		if n.op == "!~" {
+			m = "(not " + m + ")"
+		}
+		return m, sortBool, nil
+	}
+
+	right, _, err := t.emit(n.children[1], sort, vars, funcs)
+	if err != nil {
+		return "", "", err
+	}
+	op := n.op
+	switch op {
+	case "==":
+		op = "="
+	case "!=":
+		op = "distinct"
+	}
+	if sort == sortString && op != "=" && op != "distinct" {
+		// lexicographic order
+		switch op {
+		case "<":
+			return "(str.< " + left + " " + right + ")", sortBool, nil
+		case "<=":
+			return "(str.<= " + left + " " + right + ")", sortBool, nil
+		case ">":
+			return "(str.< " + right + " " + left + ")", sortBool, nil
+		case ">=":
+			return "(str.<= " + right + " " + left + ")", sortBool, nil
+		}
+	}
+
+	return "(" + op + " " + left + " " + right + ")", sortBool, nil
+}
+
+func realLiteral(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	if f < 0 {
+		return "(- " + s[1:] + ")"
+	}
+
+	return s
+}
+
+// SMT-LIB 2 string literals escape double quotes by doubling them
+func stringLiteral(s string) string {
+	return "\"" + strings.Replace(s, "\"", "\"\"", -1) + "\""
+}
@@ -0,0 +1,123 @@
+package verification
+
+import (
+	"testing"
+
+	"github.com/Knetic/govaluate"
+
+	c "../common"
+)
+
+func init() {
+	// NofM registers itself in core, which imports this package. only its signature matters to translations
+	c.RegisterRuleFunction(c.RuleFunction{
+		Name:    "NofM",
+		Args:    []string{c.SortReal, c.SortString},
+		Returns: c.SortOutcome,
+		Func:    func(args ...interface{}) (interface{}, error) { return args, nil },
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		term    string
+		mayDeny bool
+	}{
+		{"ternary", "Amount > 100 ? 'deny' : 'nil'", "(ite (> Amount 100.0) Deny Nil)", true},
+		{"parenthesized else", "Amount > 100 ? 'deny' : (Amount > 50 ? 'allow' : 'nil')", "(ite (> Amount 100.0) Deny (ite (> Amount 50.0) Allow Nil))", true},
+		{"nested ternary", "Amount > 100 ? Amount > 150 : false ? 'deny' : 'nil'", "(ite (ite (> Amount 100.0) (> Amount 150.0) false) Deny Nil)", true},
+		{"in", "DestAccount in ('AB12', 'CD34') ? 'allow' : 'nil'", "(ite (or (= DestAccount \"AB12\") (= DestAccount \"CD34\")) Allow Nil)", false},
+		{"in empty list", "DestAccount in () ? 'allow' : 'nil'", "(ite false Allow Nil)", false},
+		{"nofm", "Amount > 10000 ? NofM(2, 'ID12345,CD34YG4') : 'nil'", "(ite (> Amount 10000.0) (NofM 2.0 \"ID12345,CD34YG4\") Nil)", false},
+		{"string equality", "Recipient == 'bob' ? 'deny' : 'nil'", "(ite (= Recipient \"bob\") Deny Nil)", true},
+		{"string inequality", "Recipient != 'bob' ? 'deny' : 'nil'", "(ite (distinct Recipient \"bob\") Deny Nil)", true},
+		{"string order", "Recipient > 'm' ? 'deny' : 'nil'", "(ite (str.< \"m\" Recipient) Deny Nil)", true},
+		{"string order or equal", "Recipient <= 'm' ? 'deny' : 'nil'", "(ite (str.<= Recipient \"m\") Deny Nil)", true},
+		{"number comparison", "Amount >= 10.5 && Amount <= -3 ? 'deny' : 'nil'", "(ite (and (>= Amount 10.5) (<= Amount (- 3.0))) Deny Nil)", true},
+		{"arithmetic", "Amount * 2 + Balance > 1000 ? 'deny' : 'nil'", "(ite (> (+ (* Amount 2.0) Balance) 1000.0) Deny Nil)", true},
+		{"negation", "!(Amount < 5) ? 'allow' : 'nil'", "(ite (not (< Amount 5.0)) Allow Nil)", false},
+		{"pattern", "Recipient =~ '^ac' ? 'deny' : 'nil'", "(ite (matches Recipient \"^ac\") Deny Nil)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := Translate(tt.expr)
+			if err != nil {
+				t.Fatalf("Translate(%q): %v", tt.expr, err)
+			}
+			if tr.Term != tt.term {
+				t.Errorf("Translate(%q) = %s, want %s", tt.expr, tr.Term, tt.term)
+			}
+			if tr.Sort != sortOutcome {
+				t.Errorf("Translate(%q) is of sort %s, want %s", tt.expr, tr.Sort, sortOutcome)
+			}
+			if tr.MayDeny != tt.mayDeny {
+				t.Errorf("Translate(%q).MayDeny = %v, want %v", tt.expr, tr.MayDeny, tt.mayDeny)
+			}
+		})
+	}
+}
+
+func TestTranslateRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		// govaluate reads (Amount > 100 ? 'deny' : Amount > 50) ? 'allow' : 'nil' and fails on 'deny' ? 'allow'
+		{"unparenthesized else ternary", "Amount > 100 ? 'deny' : Amount > 50 ? 'allow' : 'nil'"},
+		// (Amount > 100 ? Amount > 150) ? 'allow' is nil when Amount <= 100, which govaluate won't take for a condition
+		{"unparenthesized then ternary", "Amount > 100 ? Amount > 150 ? 'allow' : 'nil' : 'deny'"},
+		{"else without ternary", "Amount > 100 : 'deny'"},
+		{"coalesce", "Recipient ?? 'bob' == 'bob' ? 'deny' : 'nil'"},
+		{"modulus", "Amount % 2 == 0 ? 'deny' : 'nil'"},
+		{"bitwise", "Amount & 1 == 1 ? 'deny' : 'nil'"},
+		{"unknown outcome", "Amount > 100 ? 'maybe' : 'nil'"},
+		{"unknown variable", "Fee > 100 ? 'deny' : 'nil'"},
+		{"non boolean condition", "Amount ? 'deny' : 'nil'"},
+		{"branches of different sorts", "Amount > 100 ? Amount : 'nil'"},
+		{"ternary without else", "Amount > 100 ? 'allow'"},
+		{"value ternary without else", "(Amount > 100 ? 5) > 3 ? 'deny' : 'nil'"},
+		{"wrong number of arguments", "Amount > 100 ? NofM(2) : 'nil'"},
+		{"malformed", "Amount > ? 'deny'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := Translate(tt.expr)
+			if err == nil {
+				t.Errorf("Translate(%q) = %s, want an error", tt.expr, tr.Term)
+			}
+		})
+	}
+}
+
+// translations must read nested ternaries the way govaluate evaluates them, left to right
+func TestTranslateNestedTernaryOrder(t *testing.T) {
+	expr := "Amount > 100 ? Amount > 150 : false ? 'deny' : 'nil'"
+	want := map[float64]string{200: "deny", 120: "nil", 70: "nil"}
+
+	e, err := govaluate.NewEvaluableExpression(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for amount, outcome := range want {
+		r, err := e.Evaluate(map[string]interface{}{"Amount": amount})
+		if err != nil {
+			t.Fatalf("govaluate on Amount %v: %v", amount, err)
+		}
+		if r != outcome {
+			t.Fatalf("govaluate on Amount %v = %v, want %v", amount, r, outcome)
+		}
+	}
+
+	tr, err := Translate(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (Amount > 100 ? Amount > 150 : false) ? 'deny' : 'nil', i.e., deny above 150 only
+	if tr.Term != "(ite (ite (> Amount 100.0) (> Amount 150.0) false) Deny Nil)" {
+		t.Errorf("Translate(%q) = %s", expr, tr.Term)
+	}
+}
@@ -1,14 +1,12 @@
-package main
+package verification
 
-// #cgo LDFLAGS: -L. -lz3
-// #define MAX_ASSUMPTIONS 1024
+// #cgo LDFLAGS: -L${SRCDIR} -lz3
 // #include "../c/z3.h"
 // #include <stdlib.h>
 // void error_handler(Z3_context c, Z3_error_code e)
 // {
-// 		printf("Error code: %d\n", e);
-// 	    fprintf(stderr,"BUG: %s.\n", "Incorrect use of Z3");
-//      exit(1);
+//     // do not exit: this runs inside the lambda and the transaction processor. callers check Z3_get_error_code() instead
+//     fprintf(stderr, "z3 error code: %d\n", e);
 // }
 // Z3_context mk_context_custom(Z3_config cfg, Z3_error_handler err)
 // {
@@ -37,177 +35,304 @@ package main
 // {
 //   Z3_solver_dec_ref(ctx, s);
 // }
-// Z3_string check_sat(Z3_string p, Z3_string a)
-// {
-//     Z3_model m = 0;
-//     Z3_context c = mk_context();
-//     Z3_solver s = mk_solver(c);
-//     Z3_ast_vector f = Z3_parse_smtlib2_string(c, p, 0,0,0,0,0,0);
-//     for (unsigned i = 0; i < Z3_ast_vector_size(c, f); ++i) {
-//         Z3_solver_assert(c, s, Z3_ast_vector_get(c, f, i));
-//     }
-//     Z3_ast_vector b = Z3_parse_smtlib2_string(c, a, 0,0,0,0,0,0);
-//     Z3_ast assumptions[MAX_ASSUMPTIONS];
-//     unsigned num_assumptions = Z3_ast_vector_size(c, b);
-//     for (unsigned i = 0; i < num_assumptions; ++i) assumptions[i] = Z3_ast_vector_get(c, b, i);
-//     Z3_lbool result = Z3_solver_check_assumptions(c, s, num_assumptions, assumptions);
-//     switch (result) {
-//     case Z3_L_FALSE:
-//         printf("unsat\n");
-//         Z3_ast_vector core = Z3_solver_get_unsat_core(c, s);
-//         printf("vector size %d\n", Z3_ast_vector_size(c, core));
-//          for (unsigned i = 0; i < Z3_ast_vector_size(c, core); ++i) {
-//               printf("%s\n", Z3_ast_to_string(c, Z3_ast_vector_get(c, core, i)));
-//          }
-//         break;
-//     case Z3_L_UNDEF:
-//         printf("unknown\n");
-//     m = Z3_solver_get_model(c, s);
-//     if (m) Z3_model_inc_ref(c, m);
-//         printf("potential model:\n%s\n", Z3_model_to_string(c, m));
-//         break;
-//     case Z3_L_TRUE:
-//     m = Z3_solver_get_model(c, s);
-//     if (m) Z3_model_inc_ref(c, m);
-//         printf("sat\n%s\n", Z3_model_to_string(c, m));
-//         break;
-//     }
-//     if (m) Z3_model_dec_ref(c, m);
-//     del_solver(c, s);
-//     //Z3_del_context(c);
-// }
 import "C"
 
 import (
-	"fmt"
-	"go/ast"
-	"go/parser"
+	"errors"
+	"sort"
+	"strings"
+	"unsafe"
+
+	c "../common"
+)
+
+// Status is the outcome of a satisfiability check
+type Status string
+
+// Possible values of Status
+const (
+	Sat     Status = "sat"
+	Unsat   Status = "unsat"
+	Unknown Status = "unknown"
 )
 
-// CheckSat check satisfiability
-func CheckSat(p string, a string) string {
-	return C.GoString(C.check_sat(C.CString(p), C.CString(a)))
-}
-
-// RuleVariablesSet maps recongnized rule variables to their SMT type TODO TODO TODO TODO TODO transfer this back to common/config.go
-var RuleVariablesSet = map[string]string{ // Keep it in alphabetical order for easy reading
-	"Action":        "String",
-	"Amount":        "Real",
-	"Balance":       "Real",
-	"Destaccount":   "String",
-	"Initiator":     "String",
-	"Recipient":     "String",
-	"Rule":          "String",
-	"Ruletype":      "String", // this is not used currently, right?
-	"Sourceaccount": "String",
-}
-
-// FuncSignatures maps a "rule" function to its SMT signature
-var FuncSignatures = map[string]string{
-	"f": "(Real Real) Real", // TODO bogus function just for testing
-}
-
-// map go operator to corresponding smtlib operator. Note: only needed for binary operators
-func mapOp(op string) string {
-	if op == "==" {
-		return "="
-	}
-
-	return op
-}
-
-// AstWalk walks the abstract syntax tree of the expression (typically rule)
-func AstWalk(n ast.Node) (string, []string, []string) {
-	var s string
-	vars := make([]string, 0)
-	funcs := make([]string, 0)
-	switch x := n.(type) {
-	case *ast.BasicLit:
-		s = x.Value
-	case *ast.Ident:
-		s = x.Name
-		vars = append(vars, x.Name)
-	case *ast.BinaryExpr:
-		left, lvs, lfs := AstWalk(x.X)
-		right, rvs, rfs := AstWalk(x.Y)
-		op := mapOp(x.Op.String())
-		s = "(" + op + " " + left + " " + right + ")"
-		vars = append(append(vars, lvs...), rvs...)
-		funcs = append(append(funcs, lfs...), rfs...)
-	case *ast.UnaryExpr:
-		ss, vs, fs := AstWalk(x.X)
-		// add "0" to turn unary operator into binary
-		s = "(" + x.Op.String() + " 0 " + ss + ")"
-		vars = append(vars, vs...)
-		funcs = append(funcs, fs...)
-	case *ast.CallExpr:
-		s = "(" + fmt.Sprint(x.Fun) + " "
-		funcs = append(funcs, fmt.Sprint(x.Fun))
-		args := x.Args
-		for i, arg := range args {
-			ss, vs, fs := AstWalk(arg)
-			s += ss
-			if i < len(args)-1 {
-				s += " " // no "," between function arguments in z3
+// prefixes of the constants Analyze() binds rules to. hashes are hex strings which z3 won't take as names on their own
+const (
+	rulePrefix    = "r_" // the rule fires
+	outcomePrefix = "o_" // what the rule returns
+)
+
+// Result of a satisfiability check. Model maps constants to their value in the SMT-LIB 2 syntax and is only set when Status is Sat. Core lists the assumptions in the unsat core and is only set when Status is Unsat
+type Result struct {
+	Status Status            `json:"status"`
+	Model  map[string]string `json:"model,omitempty"`
+	Core   []string          `json:"core,omitempty"`
+}
+
+// Rule is a rule as stored in the state, identified by its hash
+type Rule struct {
+	Hash string `json:"rulehash"`
+	Rule string `json:"rule"`
+}
+
+// Context wraps a z3 context. It's not safe for concurrent use. Close() must be called when done with it
+type Context struct {
+	c C.Z3_context
+}
+
+// NewContext creates a z3 context
+func NewContext() *Context {
+	return &Context{c: C.mk_context()}
+}
+
+// Close frees the memory held by the z3 context. Results already returned remain valid
+func (ctx *Context) Close() {
+	if ctx.c != nil {
+		C.Z3_del_context(ctx.c)
+		ctx.c = nil
+	}
+}
+
+// Check checks the satisfiability of program under assumptions, the names of boolean constants declared in program
+func (ctx *Context) Check(program string, assumptions []string) (Result, error) {
+	if ctx.c == nil {
+		return Result{}, errors.New("z3 context is closed")
+	}
+
+	s := C.mk_solver(ctx.c)
+	defer C.del_solver(ctx.c, s)
+
+	f, err := ctx.parse(program)
+	if err != nil {
+		return Result{}, err
+	}
+	defer C.Z3_ast_vector_dec_ref(ctx.c, f)
+	for i := C.uint(0); i < C.Z3_ast_vector_size(ctx.c, f); i++ {
+		C.Z3_solver_assert(ctx.c, s, C.Z3_ast_vector_get(ctx.c, f, i))
+	}
+
+	// z3 parses the assumptions on their own so names are declared again. same name and sort, so same constant
+	a := ""
+	for _, name := range assumptions {
+		a += "(declare-const " + name + " Bool)\n(assert " + name + ")\n"
+	}
+	b, err := ctx.parse(a)
+	if err != nil {
+		return Result{}, err
+	}
+	defer C.Z3_ast_vector_dec_ref(ctx.c, b)
+	n := C.Z3_ast_vector_size(ctx.c, b)
+	asts := make([]C.Z3_ast, n+1) // +1 so that &asts[0] is valid when there are no assumptions
+	for i := C.uint(0); i < n; i++ {
+		asts[i] = C.Z3_ast_vector_get(ctx.c, b, i)
+	}
+
+	var ret Result
+	switch C.Z3_solver_check_assumptions(ctx.c, s, n, &asts[0]) {
+	case C.Z3_L_FALSE:
+		ret.Status = Unsat
+		core := C.Z3_solver_get_unsat_core(ctx.c, s)
+		C.Z3_ast_vector_inc_ref(ctx.c, core)
+		defer C.Z3_ast_vector_dec_ref(ctx.c, core)
+		for i := C.uint(0); i < C.Z3_ast_vector_size(ctx.c, core); i++ {
+			ret.Core = append(ret.Core, C.GoString(C.Z3_ast_to_string(ctx.c, C.Z3_ast_vector_get(ctx.c, core, i))))
+		}
+	case C.Z3_L_TRUE:
+		ret.Status = Sat
+		m := C.Z3_solver_get_model(ctx.c, s)
+		C.Z3_model_inc_ref(ctx.c, m)
+		defer C.Z3_model_dec_ref(ctx.c, m)
+		ret.Model = make(map[string]string)
+		for i := C.uint(0); i < C.Z3_model_get_num_consts(ctx.c, m); i++ {
+			d := C.Z3_model_get_const_decl(ctx.c, m, i)
+			name := C.GoString(C.Z3_get_symbol_string(ctx.c, C.Z3_get_decl_name(ctx.c, d)))
+			value := C.Z3_model_get_const_interp(ctx.c, m, d)
+			if value != nil {
+				ret.Model[name] = C.GoString(C.Z3_ast_to_string(ctx.c, value))
 			}
-			vars = append(vars, vs...)
-			funcs = append(funcs, fs...)
 		}
-		s += ")"
-	case *ast.ParenExpr:
-		ss, vs, fs := AstWalk(x.X)
-		s = ss
-		vars = append(vars, vs...)
-		funcs = append(funcs, fs...)
 	default:
-		fmt.Println("type ", x, " not supported")
+		ret.Status = Unknown
 	}
 
-	return s, vars, funcs
+	return ret, nil
 }
 
-// ToSMT uses output from ast walker to build SMT-LIB 2 code
-func ToSMT(es []string, mvars, mfuncs map[string]bool) string {
-	ret := ""
-	// mvars should contain all the variables from all the es. store in map to avoid duplicates
-	for v := range mvars {
-		ret += "(declare-const " + v + " " + RuleVariablesSet[v] + ")\n"
+// parse SMT-LIB 2 code. the caller decrements the reference count of the returned vector
+func (ctx *Context) parse(program string) (C.Z3_ast_vector, error) {
+	cp := C.CString(program)
+	defer C.free(unsafe.Pointer(cp))
+
+	v := C.Z3_parse_smtlib2_string(ctx.c, cp, 0, nil, nil, 0, nil, nil)
+	if code := C.Z3_get_error_code(ctx.c); code != C.Z3_OK {
+		return nil, errors.New("z3: " + C.GoString(C.Z3_get_error_msg(ctx.c, code)))
+	}
+	C.Z3_ast_vector_inc_ref(ctx.c, v)
+
+	return v, nil
+}
+
+// Check is Context.Check() on a context of its own
+func Check(program string, assumptions []string) (Result, error) {
+	ctx := NewContext()
+	defer ctx.Close()
+
+	return ctx.Check(program, assumptions)
+}
+
+// Analyze checks whether rules can all fire together, i.e., return something other than 'nil' for the same values of the rule variables. When they can't, Core lists the hashes of the rules in the unsat core. When they can, Model is an assignment of the rule variables under which they all fire
+func Analyze(rules []Rule) (Result, error) {
+	exprs := make(map[string]string, len(rules))
+	assumptions := make([]string, 0, len(rules))
+	defs := ""
+	for _, r := range rules {
+		exprs[outcomePrefix+r.Hash] = r.Rule
+		assumptions = append(assumptions, rulePrefix+r.Hash)
+		defs += DefineBool(rulePrefix+r.Hash, Fires(outcomePrefix+r.Hash))
+	}
+
+	p, err := CreateNamedSMTprogram(exprs)
+	if err != nil {
+		return Result{}, err
+	}
+
+	ret, err := Check(p+defs, assumptions)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for i, name := range ret.Core {
+		ret.Core[i] = strings.TrimPrefix(name, rulePrefix)
+	}
+	for name := range ret.Model {
+		if strings.HasPrefix(name, rulePrefix) || strings.HasPrefix(name, outcomePrefix) {
+			delete(ret.Model, name)
+		}
+	}
+
+	return ret, nil
+}
+
+// RuleVariablesSet maps rule variables to their SMT sort. It's drawn from the registry of rule variables, see common.RegisterRuleVariable()
+var RuleVariablesSet = ruleVariableSorts()
+
+func ruleVariableSorts() map[string]string {
+	ret := make(map[string]string)
+	for _, name := range c.RuleVariableNames() {
+		v, _ := c.LookupRuleVariable(name)
+		ret[name] = v.Sort
+	}
+
+	return ret
+}
+
+// FuncSignatures maps the rule functions declared in SMT programs to their SMT signature. It's drawn from the registry of rule functions, see common.RegisterRuleFunction(). functions that make rule outcomes, like NofM, are constructors of the Outcome datatype instead
+func FuncSignatures() map[string]string {
+	ret := make(map[string]string)
+	for _, name := range c.RuleFunctionNames() {
+		f, _ := c.LookupRuleFunction(name)
+		if f.Returns != sortOutcome {
+			ret[name] = f.SMTSignature()
+		}
 	}
 
-	// mfuncs should contain all the rule functions from all the es. store in map to avoid duplicates
-	for f := range mfuncs {
-		ret += "(declare-fun " + f + " " + FuncSignatures[f] + ")\n"
+	return ret
+}
+
+// ToSMT builds SMT-LIB 2 code out of translated expressions: the declarations they need followed by the SMT-LIB 2 statements in es
+func ToSMT(es []string, ts []Translation) string {
+	ret := outcomeDatatype + "\n"
+
+	// store in maps to avoid duplicates
+	mvars, mfuncs, matches := make(map[string]bool), make(map[string]bool), false
+	for _, t := range ts {
+		for _, v := range t.Vars {
+			mvars[v] = true
+		}
+		for _, f := range t.Funcs {
+			mfuncs[f] = true
+		}
+		matches = matches || t.matches
+	}
+	for _, v := range sortedKeys(mvars) {
+		ret += "(declare-const " + v + " " + RuleVariablesSet[v] + ")\n"
+	}
+	signatures := FuncSignatures()
+	for _, f := range sortedKeys(mfuncs) {
+		ret += "(declare-fun " + f + " " + signatures[f] + ")\n"
+	}
+	if matches {
+		ret += matchesDecl + "\n"
 	}
 
 	// now the es, the expressions (rules)
 	for _, e := range es {
-		ret += "(assert " + e + ")\n"
+		ret += e + "\n"
 	}
 
-	/////////////////////////////////////////////////////////////////////////ret += "(check-sat)\n(get-model)\n"
-
 	return ret
 }
 
-// CreateSMTprogram converts a set of expressions (typically rules) into a SMT-LIB 2 program
-func CreateSMTprogram(exprs []string) string {
-	retExprs := make([]string, 0)
-	mvars, mfuncs := make(map[string]bool), make(map[string]bool)
+// CreateSMTprogram converts a set of (govaluate) expressions into a SMT-LIB 2 program asserting them. Predicates are asserted to hold and rules to fire
+func CreateSMTprogram(exprs []string) (string, error) {
+	asserts := make([]string, 0, len(exprs))
+	ts := make([]Translation, 0, len(exprs))
 	for _, e := range exprs {
-		astE, err := parser.ParseExpr(e)
+		t, err := Translate(e)
 		if err != nil {
-			panic(err)
+			return "", err
 		}
 
-		smtExpr, vars, funcs := AstWalk(astE)
-		for _, v := range vars {
-			mvars[v] = true
+		switch t.Sort {
+		case sortBool:
+			asserts = append(asserts, "(assert "+t.Term+")")
+		case sortOutcome:
+			asserts = append(asserts, "(assert "+Fires(t.Term)+")")
+		default:
+			return "", errors.New("expression " + e + " is neither a predicate nor a rule")
 		}
-		for _, f := range funcs {
-			mfuncs[f] = true
+		ts = append(ts, t)
+	}
+
+	return ToSMT(asserts, ts), nil
+}
+
+// CreateNamedSMTprogram is CreateSMTprogram except that every expression is bound to a constant named after its key instead of being asserted. Predicates are bound to boolean constants: passing those names as assumptions to Check() lets z3 tell us which expressions are in an unsat core. Rules are bound to Outcome constants, see Fires() and Denies() to build assumptions out of them
+func CreateNamedSMTprogram(exprs map[string]string) (string, error) {
+	names := make([]string, 0, len(exprs))
+	for name := range exprs {
+		names = append(names, name)
+	}
+	sort.Strings(names) // so that the same rules always produce the same program
+
+	bindings := make([]string, 0, 2*len(names))
+	ts := make([]Translation, 0, len(names))
+	for _, name := range names {
+		t, err := Translate(exprs[name])
+		if err != nil {
+			return "", err
 		}
-		retExprs = append(retExprs, smtExpr)
+		if t.Sort != sortBool && t.Sort != sortOutcome {
+			return "", errors.New("expression " + exprs[name] + " is neither a predicate nor a rule")
+		}
+		bindings = append(bindings, "(declare-const "+name+" "+t.Sort+")", "(assert (= "+name+" "+t.Term+"))")
+		ts = append(ts, t)
 	}
 
-	return ToSMT(retExprs, mvars, mfuncs)
+	return ToSMT(bindings, ts), nil
+}
+
+// DefineBool binds an SMT-LIB 2 boolean expression, typically built out of names from CreateNamedSMTprogram(), to a new name
+func DefineBool(name, smtExpr string) string {
+	return "(declare-const " + name + " Bool)\n(assert (= " + name + " " + smtExpr + "))\n"
+}
+
+func sortedKeys(m map[string]bool) []string {
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+
+	return ret
 }